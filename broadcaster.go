@@ -0,0 +1,235 @@
+package eyeson
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy defines how a Broadcaster treats a subscriber whose buffer is
+// full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the subscriber has room for the event. A
+	// blocked subscriber delays all other subscribers.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered event.
+	OverflowDropOldest
+	// OverflowDropNewest discards the new event.
+	OverflowDropNewest
+	// OverflowDisconnect discards the new event and closes the subscriber.
+	OverflowDisconnect
+)
+
+// SubscriberOptions configures a subscriber of a Broadcaster.
+type SubscriberOptions struct {
+	// Buffer is the number of buffered events. Defaults to 16.
+	Buffer int
+	// Overflow is the policy applied when the buffer is full.
+	Overflow OverflowPolicy
+}
+
+// Subscriber receives the events published by a Broadcaster.
+type Subscriber struct {
+	broadcaster  *Broadcaster
+	events       chan EventInterface
+	overflow     OverflowPolicy
+	dropped      uint64
+	disconnected int32
+
+	// mu serializes sending and closing the events channel.
+	mu     sync.Mutex
+	done   chan struct{}
+	closed bool
+	once   sync.Once
+}
+
+// Events provides the channel of events. It is closed when the subscriber is
+// closed, disconnected or the broadcaster is closed.
+func (s *Subscriber) Events() <-chan EventInterface {
+	return s.events
+}
+
+// Dropped provides the number of events dropped for this subscriber.
+func (s *Subscriber) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Disconnected reports whether the subscriber has been closed by the
+// OverflowDisconnect policy.
+func (s *Subscriber) Disconnected() bool {
+	return atomic.LoadInt32(&s.disconnected) == 1
+}
+
+// Close unsubscribes and closes the events channel.
+func (s *Subscriber) Close() {
+	s.once.Do(func() { close(s.done) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *Subscriber) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.broadcaster.remove(s)
+}
+
+func (s *Subscriber) drop() {
+	atomic.AddUint64(&s.dropped, 1)
+	atomic.AddUint64(&s.broadcaster.dropped, 1)
+}
+
+// send delivers an event according to the overflow policy.
+func (s *Subscriber) send(ctx context.Context, event EventInterface) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+		return
+	default:
+	}
+
+	switch s.overflow {
+	case OverflowBlock:
+		select {
+		case s.events <- event:
+		case <-s.done:
+		case <-ctx.Done():
+			s.drop()
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.events <- event:
+				return
+			default:
+			}
+			select {
+			case <-s.events:
+				s.drop()
+			default:
+			}
+		}
+	case OverflowDropNewest:
+		s.drop()
+	case OverflowDisconnect:
+		s.drop()
+		atomic.StoreInt32(&s.disconnected, 1)
+		s.closeLocked()
+	}
+}
+
+// Broadcaster distributes the events of one observer connection to multiple
+// independent subscribers, each with its own buffer and overflow policy.
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*Subscriber]struct{}
+	closed      bool
+	dropped     uint64
+}
+
+// NewBroadcaster creates a new Broadcaster without subscribers.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: map[*Subscriber]struct{}{}}
+}
+
+// Subscribe adds a subscriber receiving all events published from now on.
+// Subscribers of a closed broadcaster receive a closed channel.
+func (b *Broadcaster) Subscribe(options *SubscriberOptions) *Subscriber {
+	opts := SubscriberOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 16
+	}
+	s := &Subscriber{
+		broadcaster: b,
+		events:      make(chan EventInterface, opts.Buffer),
+		overflow:    opts.Overflow,
+		done:        make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.closed = true
+		close(s.events)
+		return s
+	}
+	b.subscribers[s] = struct{}{}
+	return s
+}
+
+func (b *Broadcaster) remove(s *Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, s)
+}
+
+// Subscribers provides the number of active subscribers.
+func (b *Broadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Dropped provides the number of events dropped over all subscribers.
+func (b *Broadcaster) Dropped() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+// Publish passes an event to all subscribers. Events blocked by the context
+// of a blocking subscriber are counted as dropped.
+func (b *Broadcaster) Publish(ctx context.Context, event EventInterface) {
+	b.mu.Lock()
+	subscribers := make([]*Subscriber, 0, len(b.subscribers))
+	for s := range b.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	b.mu.Unlock()
+
+	for _, s := range subscribers {
+		s.send(ctx, event)
+	}
+}
+
+// Run publishes all events of the channel until the context is done or the
+// channel is closed, and closes the broadcaster afterwards.
+func (b *Broadcaster) Run(ctx context.Context, events <-chan EventInterface) error {
+	defer b.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			b.Publish(ctx, event)
+		}
+	}
+}
+
+// Close closes all subscribers. Later subscriptions receive a closed channel.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	b.closed = true
+	subscribers := make([]*Subscriber, 0, len(b.subscribers))
+	for s := range b.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	b.mu.Unlock()
+
+	for _, s := range subscribers {
+		s.Close()
+	}
+}
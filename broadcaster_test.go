@@ -0,0 +1,119 @@
+package eyeson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func chatEvent(content string) *Chat {
+	return &Chat{EventBase: EventBase{Type: "chat"}, Content: content}
+}
+
+func drain(s *Subscriber) []string {
+	contents := []string{}
+	for {
+		select {
+		case ev, ok := <-s.Events():
+			if !ok {
+				return contents
+			}
+			contents = append(contents, ev.(*Chat).Content)
+		default:
+			return contents
+		}
+	}
+}
+
+func TestBroadcaster_overflowPolicies(t *testing.T) {
+	b := NewBroadcaster()
+	oldest := b.Subscribe(&SubscriberOptions{Buffer: 2, Overflow: OverflowDropOldest})
+	newest := b.Subscribe(&SubscriberOptions{Buffer: 2, Overflow: OverflowDropNewest})
+	disconnect := b.Subscribe(&SubscriberOptions{Buffer: 2, Overflow: OverflowDisconnect})
+
+	ctx := context.Background()
+	for _, content := range []string{"1", "2", "3"} {
+		b.Publish(ctx, chatEvent(content))
+	}
+
+	if got := drain(oldest); len(got) != 2 || got[0] != "2" || got[1] != "3" {
+		t.Errorf("Expected drop-oldest to keep 2 and 3, got %v", got)
+	}
+	if got := drain(newest); len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Errorf("Expected drop-newest to keep 1 and 2, got %v", got)
+	}
+	if got := drain(disconnect); len(got) != 2 || !disconnect.Disconnected() {
+		t.Errorf("Expected disconnected subscriber with 2 events, got %v", got)
+	}
+	if _, ok := <-disconnect.Events(); ok {
+		t.Error("Expected channel of disconnected subscriber to be closed")
+	}
+	if oldest.Dropped() != 1 || newest.Dropped() != 1 || disconnect.Dropped() != 1 {
+		t.Errorf("Unexpected drop counters %d %d %d", oldest.Dropped(), newest.Dropped(), disconnect.Dropped())
+	}
+	if b.Dropped() != 3 || b.Subscribers() != 2 {
+		t.Errorf("Expected 3 drops and 2 subscribers, got %d and %d", b.Dropped(), b.Subscribers())
+	}
+}
+
+func TestBroadcaster_block(t *testing.T) {
+	b := NewBroadcaster()
+	s := b.Subscribe(&SubscriberOptions{Buffer: 1, Overflow: OverflowBlock})
+	ctx := context.Background()
+	b.Publish(ctx, chatEvent("1"))
+
+	published := make(chan struct{})
+	go func() {
+		b.Publish(ctx, chatEvent("2"))
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatal("Expected publish to block on full subscriber")
+	case <-time.After(20 * time.Millisecond):
+	}
+	<-s.Events()
+	<-published
+	if ev := <-s.Events(); ev.(*Chat).Content != "2" {
+		t.Errorf("Expected second event, got %v", ev)
+	}
+
+	// closing a blocked subscriber releases the publisher
+	b.Publish(ctx, chatEvent("3"))
+	closed := make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		s.Close()
+		close(closed)
+	}()
+	b.Publish(ctx, chatEvent("4"))
+	<-closed
+	if b.Subscribers() != 0 {
+		t.Error("Expected subscriber to be removed")
+	}
+}
+
+func TestBroadcaster_run(t *testing.T) {
+	b := NewBroadcaster()
+	first := b.Subscribe(nil)
+	second := b.Subscribe(nil)
+
+	events := make(chan EventInterface, 2)
+	events <- chatEvent("a")
+	events <- chatEvent("b")
+	close(events)
+	if err := b.Run(context.Background(), events); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, s := range []*Subscriber{first, second} {
+		if got := drain(s); len(got) != 2 {
+			t.Errorf("Expected both events, got %v", got)
+		}
+		if _, ok := <-s.Events(); ok {
+			t.Error("Expected channel to be closed after Run")
+		}
+	}
+	if _, ok := <-b.Subscribe(nil).Events(); ok {
+		t.Error("Expected closed channel when subscribing to closed broadcaster")
+	}
+}
@@ -0,0 +1,225 @@
+package eyeson
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ChatCommand is a slash command sent to a ChatBot.
+type ChatCommand struct {
+	// Name is the command without prefix, e.g. record for /record start.
+	Name string
+	// Args are the arguments following the command. Double quotes group
+	// arguments containing spaces.
+	Args []string
+	// Text is the unparsed text following the command.
+	Text string
+	// Chat is the chat event carrying the command.
+	Chat *Chat
+	// Sender is the participant who sent the command, if known.
+	Sender Participant
+	// SenderKnown reports whether the sender is a known participant.
+	SenderKnown bool
+}
+
+// CommandHandler handles a chat command. A non-empty reply, or the error,
+// is sent to the chat.
+type CommandHandler func(ctx context.Context, cmd *ChatCommand) (reply string, err error)
+
+// CommandPermission decides whether the sender of a command may run it.
+type CommandPermission func(cmd *ChatCommand) bool
+
+// NonGuestOnly permits known participants who are not guests.
+func NonGuestOnly(cmd *ChatCommand) bool {
+	return cmd.SenderKnown && !cmd.Sender.Guest
+}
+
+// CommandOption configures a command registered with a ChatBot.
+type CommandOption func(*botCommand)
+
+// WithPermission restricts a command to senders accepted by the permission.
+func WithPermission(permission CommandPermission) CommandOption {
+	return func(c *botCommand) {
+		c.permission = permission
+	}
+}
+
+type botCommand struct {
+	description string
+	handler     CommandHandler
+	permission  CommandPermission
+}
+
+// ChatBot runs slash commands sent to the chat of a meeting and replies via
+// the chat of its user. It answers /help with a list of all commands.
+type ChatBot struct {
+	user  *UserService
+	state *RoomState
+
+	mu       sync.RWMutex
+	prefix   string
+	commands map[string]botCommand
+	onError  func(err error)
+}
+
+// NewChatBot creates a bot replying as the given user.
+func NewChatBot(user *UserService) *ChatBot {
+	return &ChatBot{
+		user:     user,
+		state:    NewRoomState(),
+		prefix:   "/",
+		commands: map[string]botCommand{},
+	}
+}
+
+// SetPrefix changes the command prefix, "/" by default.
+func (b *ChatBot) SetPrefix(prefix string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prefix = prefix
+}
+
+// OnError sets a function called if a command fails or a reply cannot be
+// sent. Errors of commands are not posted to the chat.
+func (b *ChatBot) OnError(handler func(err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = handler
+}
+
+// Command registers a handler for the command with the given name.
+func (b *ChatBot) Command(name, description string, handler CommandHandler, options ...CommandOption) {
+	cmd := botCommand{description: description, handler: handler}
+	for _, option := range options {
+		option(&cmd)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commands[strings.ToLower(name)] = cmd
+}
+
+// Run handles all events of the channel until the context is done or the
+// channel is closed.
+func (b *ChatBot) Run(ctx context.Context, events <-chan EventInterface) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			b.Handle(ctx, event)
+		}
+	}
+}
+
+// Handle processes a single observer event. Room and participant updates are
+// used to track the participants; chat messages are parsed for commands.
+// Handle can be registered with EventRouter.OnAny.
+func (b *ChatBot) Handle(ctx context.Context, event EventInterface) {
+	chat, ok := event.(*Chat)
+	if !ok {
+		b.state.Apply(event)
+		return
+	}
+	if b.user.Data != nil && chat.UserID == b.user.Data.User.ID {
+		return
+	}
+	b.mu.RLock()
+	prefix, onError := b.prefix, b.onError
+	b.mu.RUnlock()
+	content := strings.TrimSpace(chat.Content)
+	if !strings.HasPrefix(content, prefix) || len(content) == len(prefix) {
+		return
+	}
+
+	cmd := parseChatCommand(strings.TrimPrefix(content, prefix))
+	cmd.Chat = chat
+	cmd.Sender, cmd.SenderKnown = b.state.Participant(chat.UserID)
+	reply, err := b.run(ctx, prefix, cmd)
+	if err != nil && onError != nil {
+		onError(fmt.Errorf("Command %s failed: %w", cmd.Name, err))
+	}
+	if reply == "" {
+		return
+	}
+	if err := b.user.ChatContext(ctx, reply); err != nil && onError != nil {
+		onError(err)
+	}
+}
+
+// run executes a command and provides the reply. The error of a failed
+// command is returned along with a generic reply, so it is not disclosed in
+// the chat.
+func (b *ChatBot) run(ctx context.Context, prefix string, cmd *ChatCommand) (string, error) {
+	if cmd.Name == "help" {
+		return b.help(prefix), nil
+	}
+	b.mu.RLock()
+	command, ok := b.commands[cmd.Name]
+	b.mu.RUnlock()
+	if !ok {
+		return fmt.Sprintf("Unknown command %s%s, try %shelp", prefix, cmd.Name, prefix), nil
+	}
+	if command.permission != nil && !command.permission(cmd) {
+		return fmt.Sprintf("You are not allowed to use %s%s", prefix, cmd.Name), nil
+	}
+	reply, err := command.handler(ctx, cmd)
+	if err != nil {
+		return fmt.Sprintf("%s%s failed", prefix, cmd.Name), err
+	}
+	return reply, nil
+}
+
+func (b *ChatBot) help(prefix string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	names := make([]string, 0, len(b.commands))
+	for name := range b.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{"Available commands:"}
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s%s - %s", prefix, name, b.commands[name].description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseChatCommand splits the text of a command into name and arguments.
+func parseChatCommand(text string) *ChatCommand {
+	name := text
+	rest := ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		name, rest = text[:i], strings.TrimSpace(text[i:])
+	}
+	cmd := &ChatCommand{Name: strings.ToLower(name), Text: rest, Args: []string{}}
+
+	var arg strings.Builder
+	inQuotes, hasArg := false, false
+	for _, r := range rest {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if hasArg {
+				cmd.Args = append(cmd.Args, arg.String())
+				arg.Reset()
+				hasArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		cmd.Args = append(cmd.Args, arg.String())
+	}
+	return cmd
+}
@@ -0,0 +1,112 @@
+package eyeson_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"github.com/eyeson-team/eyeson-go/eyesontest"
+)
+
+var errNope = errors.New("nope")
+
+func TestChatBot(t *testing.T) {
+	fake := eyesontest.NewServer("bot-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("bot-key", eyeson.WithCustomEndpoint(fake.URL))
+	bot, err := client.Rooms.Join("botroom", "bot", nil)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	host, err := client.Rooms.Join("botroom", "host", nil)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	guest, err := client.Rooms.GuestJoin(bot.Data.Room.GuestToken, "", "guest", "")
+	if err != nil {
+		t.Fatalf("GuestJoin failed: %v", err)
+	}
+
+	chatBot := eyeson.NewChatBot(bot)
+	commandErrs := make(chan error, 1)
+	chatBot.OnError(func(err error) {
+		if errors.Is(err, errNope) {
+			commandErrs <- err
+			return
+		}
+		t.Errorf("Failed to reply: %v", err)
+	})
+	var got *eyeson.ChatCommand
+	chatBot.Command("play", "Play a video", func(ctx context.Context, cmd *eyeson.ChatCommand) (string, error) {
+		got = cmd
+		return "Playing " + cmd.Args[0], nil
+	}, eyeson.WithPermission(eyeson.NonGuestOnly))
+	chatBot.Command("fail", "Always fails", func(ctx context.Context, cmd *eyeson.ChatCommand) (string, error) {
+		return "", errNope
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.Observer.Connect(ctx, "botroom")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	go chatBot.Run(ctx, events)
+	for fake.Observers("botroom") == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	expectReply := func(sender *eyeson.UserService, content, reply string) {
+		t.Helper()
+		if err := sender.Chat(content); err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+		for {
+			room, _ := fake.Room("botroom")
+			last := room.Messages[len(room.Messages)-1]
+			if last.UserID == bot.Data.User.ID {
+				if !strings.HasPrefix(last.Content, reply) {
+					t.Errorf("Expected reply %q, got %q", reply, last.Content)
+				}
+				return
+			}
+			select {
+			case <-ctx.Done():
+				t.Fatalf("Timeout waiting for reply to %q", content)
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}
+	expectReply(guest, "/play https://example.com/a.mp4", "You are not allowed to use /play")
+	expectReply(host, `/PLAY "https://example.com/b c.mp4" loop`, "Playing https://example.com/b c.mp4")
+	if got == nil || got.Name != "play" || len(got.Args) != 2 || got.Args[1] != "loop" ||
+		got.Sender.Name != "host" || got.Chat.UserID != host.Data.User.ID {
+		t.Errorf("Unexpected command %+v", got)
+	}
+	expectReply(guest, "/fail", "/fail failed")
+	if room, _ := fake.Room("botroom"); strings.Contains(room.Messages[len(room.Messages)-1].Content, "nope") {
+		t.Error("Expected command error not to be posted to the chat")
+	}
+	select {
+	case err := <-commandErrs:
+		if err.Error() != "Command fail failed: nope" {
+			t.Errorf("Unexpected command error %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timeout waiting for command error")
+	}
+	expectReply(guest, "/unknown", "Unknown command /unknown, try /help")
+	expectReply(guest, "/help", "Available commands:\n/fail - Always fails\n/play - Play a video")
+
+	if err := host.Chat("no command"); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	room, _ := fake.Room("botroom")
+	if last := room.Messages[len(room.Messages)-1]; last.Content != "no command" {
+		t.Errorf("Expected no reply to plain chat, got %q", last.Content)
+	}
+}
@@ -0,0 +1,135 @@
+package eyeson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits the amount of an error response body that is read
+// and kept within an APIError.
+const maxErrorBodySize = 64 * 1024
+
+// Sentinel errors an APIError resolves to, depending on its status code. Use
+// errors.Is to check for a specific class of failure.
+var (
+	// ErrBadRequest is returned for status 400.
+	ErrBadRequest = errors.New("Bad request! Check your request parameters to be valid")
+	// ErrUnauthorized is returned for status 401.
+	ErrUnauthorized = errors.New("Authorization failed! Check the API key to be valid")
+	// ErrForbidden is returned for status 403.
+	ErrForbidden = errors.New("Forbidden! The request is not permitted for this key")
+	// ErrNotFound is returned for status 404.
+	ErrNotFound = errors.New("Not found! Resource does not exist or expired")
+	// ErrConflict is returned for status 409.
+	ErrConflict = errors.New("Conflict! The resource is in a conflicting state")
+	// ErrGone is returned for status 410.
+	ErrGone = errors.New("Gone! The resource is no longer available")
+	// ErrUnprocessable is returned for status 422.
+	ErrUnprocessable = errors.New("Unprocessable! The request parameters could not be processed")
+	// ErrRateLimited is returned for status 429.
+	ErrRateLimited = errors.New("Rate limited! Too many requests")
+	// ErrServer is returned for any status of 500 and above.
+	ErrServer = errors.New("Server error! The eyeson API failed to process the request")
+	// ErrUnknown is returned for any other unsuccessful status.
+	ErrUnknown = errors.New("Unknown error! Request failed for an unknown error")
+)
+
+// APIError is returned for every response of the eyeson API with an
+// unsuccessful status code. It unwraps to one of the sentinel errors like
+// ErrNotFound or ErrRateLimited.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Method is the HTTP method of the failed request.
+	Method string
+	// Path is the URL path of the failed request. Access keys and guest
+	// tokens are redacted like in log output.
+	Path string
+	// Message is the error message provided by the API, if any.
+	Message string
+	// Body is the raw response body, limited to 64KiB.
+	Body []byte
+	// RequestID is the value of the X-Request-Id response header, if any.
+	RequestID string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Unwrap().Error()
+	}
+	return fmt.Sprintf("%s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// Unwrap provides the sentinel error matching the status code.
+func (e *APIError) Unwrap() error {
+	c := e.StatusCode
+	switch {
+	case c == http.StatusBadRequest:
+		return ErrBadRequest
+	case c == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case c == http.StatusForbidden:
+		return ErrForbidden
+	case c == http.StatusNotFound:
+		return ErrNotFound
+	case c == http.StatusConflict:
+		return ErrConflict
+	case c == http.StatusGone:
+		return ErrGone
+	case c == http.StatusUnprocessableEntity:
+		return ErrUnprocessable
+	case c == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case c >= 500:
+		return ErrServer
+	default:
+		return ErrUnknown
+	}
+}
+
+// errorBody covers the error formats sent by the API.
+type errorBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// newAPIError builds an APIError from an unsuccessful response, consuming
+// its body.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			apiErr.Path = resp.Request.URL.Path
+		}
+	}
+	if resp.Body == nil {
+		return apiErr
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil || len(body) == 0 {
+		return apiErr
+	}
+	apiErr.Body = body
+
+	var decoded errorBody
+	if json.Unmarshal(body, &decoded) == nil {
+		if decoded.Error != "" {
+			apiErr.Message = decoded.Error
+		} else {
+			apiErr.Message = decoded.Message
+		}
+	} else if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
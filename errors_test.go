@@ -0,0 +1,89 @@
+package eyeson
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIError_sentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{400, ErrBadRequest},
+		{401, ErrUnauthorized},
+		{403, ErrForbidden},
+		{404, ErrNotFound},
+		{409, ErrConflict},
+		{410, ErrGone},
+		{422, ErrUnprocessable},
+		{429, ErrRateLimited},
+		{500, ErrServer},
+		{503, ErrServer},
+		{418, ErrUnknown},
+	}
+	for _, tt := range tests {
+		err := &APIError{StatusCode: tt.status}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("APIError with status %d is not %v", tt.status, tt.want)
+		}
+	}
+}
+
+func TestAPIError_fromResponse(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(404)
+		w.Write([]byte(`{"error":"room expired"}`))
+	})
+
+	req, _ := client.NewRequest("DELETE", "/rooms/token", nil)
+	_, err := client.Do(req, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 404 {
+		t.Errorf("APIError status code is %d, want 404", apiErr.StatusCode)
+	}
+	if apiErr.Method != "DELETE" || apiErr.Path != "/rooms/token" {
+		t.Errorf("APIError request is %s %s, want DELETE /rooms/token", apiErr.Method, apiErr.Path)
+	}
+	if apiErr.Message != "room expired" {
+		t.Errorf("APIError message is %q, want room expired", apiErr.Message)
+	}
+	if apiErr.RequestID != "req-42" {
+		t.Errorf("APIError request id is %q, want req-42", apiErr.RequestID)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAPIError_redactPath(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	userClient := client.UserClient()
+
+	mux.HandleFunc("/rooms/secret-access-key", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+
+	req, _ := userClient.NewRequest("GET", "/rooms/secret-access-key", nil)
+	_, err := userClient.Do(req, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if apiErr.Path != "/rooms/[REDACTED]" || strings.Contains(err.Error(), "secret-access-key") {
+		t.Errorf("Expected access key to be redacted, got %v", err)
+	}
+}
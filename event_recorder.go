@@ -0,0 +1,184 @@
+package eyeson
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedEvent is a single line of a recorded observer stream.
+type RecordedEvent struct {
+	Time  time.Time       `json:"time"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// EventRecorder writes observer events as JSON lines with their time of
+// arrival, to be replayed by ReplayEvents.
+type EventRecorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+	now func() time.Time
+}
+
+// NewEventRecorder creates a recorder writing to w.
+func NewEventRecorder(w io.Writer) *EventRecorder {
+	return &EventRecorder{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// Record writes a single event.
+func (r *EventRecorder) Record(event EventInterface) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.write(event.GetType(), raw)
+}
+
+// RecordMessage writes a single event message as received from the observer,
+// keeping attributes unknown to the event structs. Call it from
+// ObserverOptions.OnMessage, errors are available by Err as well.
+func (r *EventRecorder) RecordMessage(raw json.RawMessage) error {
+	var msgBase EventBase
+	if err := json.Unmarshal(raw, &msgBase); err != nil {
+		err = &EventDecodeError{Raw: raw, Err: err}
+		r.mu.Lock()
+		if r.err == nil {
+			r.err = err
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return r.write(msgBase.Type, raw)
+}
+
+func (r *EventRecorder) write(eventType string, raw json.RawMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.enc.Encode(RecordedEvent{Time: r.now(), Type: eventType, Event: raw})
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return err
+}
+
+// Err provides the first error occurred while recording.
+func (r *EventRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Tee records all events of the channel and passes them on to the returned
+// channel, which is closed once the context is done or the input channel is
+// closed. Recording errors are available by Err.
+func (r *EventRecorder) Tee(ctx context.Context, events <-chan EventInterface) <-chan EventInterface {
+	out := make(chan EventInterface, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				r.Record(event)
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// ReplayOptions configures ReplayEvents.
+type ReplayOptions struct {
+	// Speed scales the recorded delays between events, e.g. 1 replays in real
+	// time and 10 ten times faster. Zero replays without delays.
+	Speed float64
+	// OnError is called for lines that cannot be decoded, which are skipped,
+	// and for read errors, which end the replay.
+	OnError func(err error)
+}
+
+// ReplayEvents reads a stream recorded by an EventRecorder and provides its
+// events like ObserverService.Connect. The channel is closed at the end of
+// the stream or when the context is done.
+func ReplayEvents(ctx context.Context, r io.Reader, options *ReplayOptions) <-chan EventInterface {
+	opts := ReplayOptions{}
+	if options != nil {
+		opts = *options
+	}
+	reportError := func(err error) {
+		if opts.OnError != nil {
+			opts.OnError(err)
+		}
+	}
+
+	out := make(chan EventInterface, 1)
+	go func() {
+		defer close(out)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		var last time.Time
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			var recorded RecordedEvent
+			if err := json.Unmarshal(scanner.Bytes(), &recorded); err != nil {
+				reportError(&EventDecodeError{Raw: append([]byte(nil), scanner.Bytes()...), Err: err})
+				continue
+			}
+			event, err := decodeRecordedEvent(recorded)
+			if err != nil {
+				reportError(err)
+				continue
+			}
+
+			if opts.Speed > 0 && !last.IsZero() && recorded.Time.After(last) {
+				delay := time.Duration(float64(recorded.Time.Sub(last)) / opts.Speed)
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+			last = recorded.Time
+
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			reportError(err)
+		}
+	}()
+	return out
+}
+
+// decodeRecordedEvent decodes a recorded event including the synthetic
+// events of the observer.
+func decodeRecordedEvent(recorded RecordedEvent) (EventInterface, error) {
+	if recorded.Type == "connection_state" {
+		state := &ConnectionState{}
+		if err := json.Unmarshal(recorded.Event, state); err != nil {
+			return nil, &EventDecodeError{Type: recorded.Type, Raw: recorded.Event, Err: err}
+		}
+		return state, nil
+	}
+	return decodeEvent(recorded.Event)
+}
@@ -0,0 +1,119 @@
+package eyeson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventRecorder_roundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewEventRecorder(&buf)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{0, 100 * time.Millisecond, 300 * time.Millisecond, 400 * time.Millisecond}
+	calls := 0
+	recorder.now = func() time.Time {
+		offset := offsets[calls]
+		calls++
+		return start.Add(offset)
+	}
+
+	events := make(chan EventInterface, 4)
+	events <- &ConnectionState{EventBase: EventBase{Type: "connection_state"}, State: ConnectionConnected}
+	events <- &Chat{EventBase: EventBase{Type: "chat"}, Content: "hi", UserID: "u1"}
+	events <- &PodiumUpdate{EventBase: EventBase{Type: "podium_update"},
+		Podium: []PodiumPosition{{UserID: "u1", Width: 640, ZIndex: 1}}}
+	events <- &RawEvent{EventBase: EventBase{Type: "brand_new"}, Raw: []byte(`{"type":"brand_new","x":1}`)}
+	close(events)
+	forwarded := 0
+	for range recorder.Tee(context.Background(), events) {
+		forwarded++
+	}
+	if forwarded != 4 || recorder.Err() != nil {
+		t.Fatalf("Expected 4 forwarded events, got %d (%v)", forwarded, recorder.Err())
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Fatalf("Expected 4 lines, got %d", lines)
+	}
+
+	replayed := []EventInterface{}
+	for ev := range ReplayEvents(context.Background(), bytes.NewReader(buf.Bytes()), nil) {
+		replayed = append(replayed, ev)
+	}
+	if len(replayed) != 4 {
+		t.Fatalf("Expected 4 replayed events, got %d", len(replayed))
+	}
+	if ev, ok := replayed[0].(*ConnectionState); !ok || ev.State != ConnectionConnected {
+		t.Errorf("Expected connection state, got %#v", replayed[0])
+	}
+	if ev, ok := replayed[1].(*Chat); !ok || ev.Content != "hi" || ev.UserID != "u1" {
+		t.Errorf("Expected chat, got %#v", replayed[1])
+	}
+	if ev, ok := replayed[2].(*PodiumUpdate); !ok || ev.Podium[0].Width != 640 || ev.Podium[0].ZIndex != 1 {
+		t.Errorf("Expected podium update, got %#v", replayed[2])
+	}
+	if ev, ok := replayed[3].(*RawEvent); !ok || string(ev.Raw) != `{"type":"brand_new","x":1}` {
+		t.Errorf("Expected raw event, got %#v", replayed[3])
+	}
+}
+
+func TestEventRecorder_unknownFields(t *testing.T) {
+	received := `{"type":"room_update","content":{"id":"r1","new_flag":true},"revision":7}`
+	var buf bytes.Buffer
+	recorder := NewEventRecorder(&buf)
+	if err := recorder.RecordMessage(json.RawMessage(received)); err != nil {
+		t.Fatalf("Failed to record message: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"new_flag":true`) || !strings.Contains(buf.String(), `"revision":7`) {
+		t.Fatalf("Expected unknown fields to be recorded, got %s", buf.String())
+	}
+	if err := recorder.RecordMessage(json.RawMessage(`{"type":`)); err == nil || recorder.Err() == nil {
+		t.Error("Expected error for invalid message")
+	}
+
+	var replayed EventInterface
+	for ev := range ReplayEvents(context.Background(), &buf, nil) {
+		replayed = ev
+	}
+	if ev, ok := replayed.(*RoomUpdate); !ok || ev.Content.ID != "r1" {
+		t.Fatalf("Expected replayed room update, got %#v", replayed)
+	}
+}
+
+func TestReplayEvents_speed(t *testing.T) {
+	stream := `{"time":"2024-01-01T12:00:00Z","type":"chat","event":{"type":"chat","content":"1"}}
+{"time":"2024-01-01T12:00:01Z","type":"chat","event":{"type":"chat","content":"2"}}
+not json
+{"time":"2024-01-01T12:00:02Z","type":"chat","event":{"type":"chat","content":"3"}}
+`
+	var errs []error
+	start := time.Now()
+	count := 0
+	for range ReplayEvents(context.Background(), strings.NewReader(stream),
+		&ReplayOptions{Speed: 40, OnError: func(err error) { errs = append(errs, err) }}) {
+		count++
+	}
+	elapsed := time.Since(start)
+	if count != 3 || len(errs) != 1 {
+		t.Errorf("Expected 3 events and 1 error, got %d and %v", count, errs)
+	}
+	if elapsed < 40*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected replay of 2s at speed 40 to take about 50ms, took %s", elapsed)
+	}
+}
+
+func TestReplayEvents_canceled(t *testing.T) {
+	stream := `{"time":"2024-01-01T12:00:00Z","type":"chat","event":{"type":"chat"}}
+{"time":"2024-01-01T13:00:00Z","type":"chat","event":{"type":"chat"}}
+`
+	ctx, cancel := context.WithCancel(context.Background())
+	events := ReplayEvents(ctx, strings.NewReader(stream), &ReplayOptions{Speed: 1})
+	<-events
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("Expected channel to be closed after cancel")
+	}
+}
@@ -0,0 +1,245 @@
+package eyeson
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// EventHandler handles an observer event.
+type EventHandler func(ctx context.Context, event EventInterface)
+
+// EventMiddleware wraps an EventHandler, e.g. to log, filter or time events.
+type EventMiddleware func(next EventHandler) EventHandler
+
+// PanicHandler is called with the recovered value and the stack trace if an
+// event handler panics.
+type PanicHandler func(event EventInterface, recovered interface{}, stack []byte)
+
+// HandlerOption configures a handler registered with an EventRouter.
+type HandlerOption func(*routeHandler)
+
+// HandlerAsync runs the handler in its own goroutine for every event instead
+// of blocking the router until it returns.
+func HandlerAsync() HandlerOption {
+	return func(h *routeHandler) {
+		h.async = true
+	}
+}
+
+type routeHandler struct {
+	handler EventHandler
+	async   bool
+}
+
+// EventRouter dispatches observer events to handlers registered by event type.
+// Serial handlers run one after another in the order of registration and
+// receive events in the order of arrival. Async handlers run in their own
+// goroutine. Panics of handlers are recovered.
+type EventRouter struct {
+	mu          sync.RWMutex
+	handlers    map[string][]routeHandler
+	any         []routeHandler
+	middlewares []EventMiddleware
+	onPanic     PanicHandler
+	wg          sync.WaitGroup
+}
+
+// NewEventRouter creates a new EventRouter without handlers.
+func NewEventRouter() *EventRouter {
+	return &EventRouter{handlers: map[string][]routeHandler{}}
+}
+
+// Use adds middlewares wrapping every handler call. The first given
+// middleware is the outermost.
+func (r *EventRouter) Use(middlewares ...EventMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// OnPanic sets the function called when a handler panics. Without it, panics
+// are recovered silently.
+func (r *EventRouter) OnPanic(handler PanicHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onPanic = handler
+}
+
+// Handle registers a handler for all events of the given type, e.g. chat or
+// podium_update.
+func (r *EventRouter) Handle(eventType string, handler EventHandler, options ...HandlerOption) {
+	h := newRouteHandler(handler, options)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[eventType] = append(r.handlers[eventType], h)
+}
+
+// OnAny registers a handler for every event, including unhandled types.
+func (r *EventRouter) OnAny(handler EventHandler, options ...HandlerOption) {
+	h := newRouteHandler(handler, options)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.any = append(r.any, h)
+}
+
+func newRouteHandler(handler EventHandler, options []HandlerOption) routeHandler {
+	h := routeHandler{handler: handler}
+	for _, option := range options {
+		option(&h)
+	}
+	return h
+}
+
+// OnRoomUpdate registers a handler for RoomUpdate events.
+func (r *EventRouter) OnRoomUpdate(handler func(*RoomUpdate), options ...HandlerOption) {
+	r.Handle("room_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*RoomUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnParticipantUpdate registers a handler for ParticipantUpdate events.
+func (r *EventRouter) OnParticipantUpdate(handler func(*ParticipantUpdate), options ...HandlerOption) {
+	r.Handle("participant_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*ParticipantUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnPodiumUpdate registers a handler for PodiumUpdate events.
+func (r *EventRouter) OnPodiumUpdate(handler func(*PodiumUpdate), options ...HandlerOption) {
+	r.Handle("podium_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*PodiumUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnRecordingUpdate registers a handler for RecordingUpdate events.
+func (r *EventRouter) OnRecordingUpdate(handler func(*RecordingUpdate), options ...HandlerOption) {
+	r.Handle("recording_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*RecordingUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnBroadcastUpdate registers a handler for BroadcastUpdate events.
+func (r *EventRouter) OnBroadcastUpdate(handler func(*BroadcastUpdate), options ...HandlerOption) {
+	r.Handle("broadcasts_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*BroadcastUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnOptionsUpdate registers a handler for OptionsUpdate events.
+func (r *EventRouter) OnOptionsUpdate(handler func(*OptionsUpdate), options ...HandlerOption) {
+	r.Handle("options_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*OptionsUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnSnapshotUpdate registers a handler for SnapshotUpdate events.
+func (r *EventRouter) OnSnapshotUpdate(handler func(*SnapshotUpdate), options ...HandlerOption) {
+	r.Handle("snapshot_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*SnapshotUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnPlaybackUpdate registers a handler for PlaybackUpdate events.
+func (r *EventRouter) OnPlaybackUpdate(handler func(*PlaybackUpdate), options ...HandlerOption) {
+	r.Handle("playback_update", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*PlaybackUpdate); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnChat registers a handler for Chat events.
+func (r *EventRouter) OnChat(handler func(*Chat), options ...HandlerOption) {
+	r.Handle("chat", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*Chat); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnCustomMessage registers a handler for CustomMessage events.
+func (r *EventRouter) OnCustomMessage(handler func(*CustomMessage), options ...HandlerOption) {
+	r.Handle("custom", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*CustomMessage); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// OnConnectionState registers a handler for ConnectionState events.
+func (r *EventRouter) OnConnectionState(handler func(*ConnectionState), options ...HandlerOption) {
+	r.Handle("connection_state", func(ctx context.Context, event EventInterface) {
+		if ev, ok := event.(*ConnectionState); ok {
+			handler(ev)
+		}
+	}, options...)
+}
+
+// Run dispatches all events of the channel until the context is done or the
+// channel is closed. It waits for running async handlers before it returns
+// the error of the context, if any.
+func (r *EventRouter) Run(ctx context.Context, events <-chan EventInterface) error {
+	defer r.wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			r.Dispatch(ctx, event)
+		}
+	}
+}
+
+// Dispatch passes a single event to all matching handlers. It returns once
+// all serial handlers have finished.
+func (r *EventRouter) Dispatch(ctx context.Context, event EventInterface) {
+	r.mu.RLock()
+	handlers := make([]routeHandler, 0, len(r.handlers[event.GetType()])+len(r.any))
+	handlers = append(handlers, r.handlers[event.GetType()]...)
+	handlers = append(handlers, r.any...)
+	middlewares := r.middlewares
+	onPanic := r.onPanic
+	r.mu.RUnlock()
+
+	for _, h := range handlers {
+		handler := h.handler
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handler = middlewares[i](handler)
+		}
+		call := func() {
+			defer func() {
+				if recovered := recover(); recovered != nil && onPanic != nil {
+					onPanic(event, recovered, debug.Stack())
+				}
+			}()
+			handler(ctx, event)
+		}
+		if h.async {
+			r.wg.Add(1)
+			go func() {
+				defer r.wg.Done()
+				call()
+			}()
+			continue
+		}
+		call()
+	}
+}
@@ -0,0 +1,131 @@
+package eyeson
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventRouter_typedHandlers(t *testing.T) {
+	router := NewEventRouter()
+	var got []string
+	router.OnChat(func(ev *Chat) { got = append(got, "chat:"+ev.Content) })
+	router.OnParticipantUpdate(func(ev *ParticipantUpdate) { got = append(got, "participant:"+ev.Participant.Name) })
+	router.OnAny(func(ctx context.Context, ev EventInterface) { got = append(got, "any:"+ev.GetType()) })
+
+	events := make(chan EventInterface, 3)
+	events <- &Chat{EventBase: EventBase{Type: "chat"}, Content: "hi"}
+	events <- &ParticipantUpdate{EventBase: EventBase{Type: "participant_update"}, Participant: Participant{Name: "mike"}}
+	events <- &PodiumUpdate{EventBase: EventBase{Type: "podium_update"}}
+	close(events)
+
+	if err := router.Run(context.Background(), events); err != nil {
+		t.Errorf("Expected nil error on closed channel, got %v", err)
+	}
+	want := []string{"chat:hi", "any:chat", "participant:mike", "any:participant_update", "any:podium_update"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestEventRouter_middleware(t *testing.T) {
+	router := NewEventRouter()
+	var order []string
+	mw := func(name string) EventMiddleware {
+		return func(next EventHandler) EventHandler {
+			return func(ctx context.Context, ev EventInterface) {
+				order = append(order, name)
+				next(ctx, ev)
+			}
+		}
+	}
+	router.Use(mw("outer"), mw("inner"))
+	router.Use(func(next EventHandler) EventHandler {
+		return func(ctx context.Context, ev EventInterface) {
+			if ev.GetType() != "custom" {
+				next(ctx, ev)
+			}
+		}
+	})
+	router.OnChat(func(ev *Chat) { order = append(order, "handler") })
+	router.OnCustomMessage(func(ev *CustomMessage) { t.Error("Expected custom message to be filtered") })
+
+	router.Dispatch(context.Background(), &Chat{EventBase: EventBase{Type: "chat"}})
+	router.Dispatch(context.Background(), &CustomMessage{EventBase: EventBase{Type: "custom"}})
+	if len(order) != 5 || order[0] != "outer" || order[1] != "inner" || order[2] != "handler" {
+		t.Errorf("Unexpected call order %v", order)
+	}
+}
+
+func TestEventRouter_panicRecovery(t *testing.T) {
+	router := NewEventRouter()
+	var recovered interface{}
+	router.OnPanic(func(ev EventInterface, r interface{}, stack []byte) {
+		recovered = r
+	})
+	called := false
+	router.OnChat(func(ev *Chat) { panic("boom") })
+	router.OnChat(func(ev *Chat) { called = true })
+
+	router.Dispatch(context.Background(), &Chat{EventBase: EventBase{Type: "chat"}})
+	if recovered != "boom" {
+		t.Errorf("Expected recovered panic, got %v", recovered)
+	}
+	if !called {
+		t.Error("Expected second handler to be called after panic")
+	}
+}
+
+func TestEventRouter_async(t *testing.T) {
+	router := NewEventRouter()
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var done []string
+	router.OnChat(func(ev *Chat) {
+		<-release
+		mu.Lock()
+		done = append(done, "async")
+		mu.Unlock()
+	}, HandlerAsync())
+	router.OnChat(func(ev *Chat) {
+		mu.Lock()
+		done = append(done, "serial")
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan EventInterface, 1)
+	events <- &Chat{EventBase: EventBase{Type: "chat"}}
+	result := make(chan error)
+	go func() { result <- router.Run(ctx, events) }()
+
+	for {
+		mu.Lock()
+		n := len(done)
+		mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-result:
+		t.Fatal("Expected Run to wait for async handler")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := <-result; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(done) != 2 || done[0] != "serial" {
+		t.Errorf("Unexpected handler order %v", done)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -30,21 +31,18 @@ func main() {
 		log.Fatal(err)
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		data, err := eyeson.NewWebhook(apiKey, r)
-		if err != nil {
-			log.Println("Could not parse request: ", err)
-			return
-		}
+	handler := eyeson.NewWebhookHandler(apiKey)
+	handler.OnError = func(r *http.Request, err error) {
+		log.Println("Could not handle webhook: ", err)
+	}
+	handler.OnRoomUpdate(func(ctx context.Context, data *eyeson.Webhook) error {
 		log.Println("Received new webhook for Room", data.Room.Name)
-		if err := logRoomUpdate(data); err != nil {
-			log.Println("Could not store data,", err)
-			return
-		}
-		w.WriteHeader(204)
+		return logRoomUpdate(data)
 	})
 
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+
 	srv := &http.Server{Addr: fmt.Sprintf(":%d", *port), Handler: mux}
 	stop := make(chan os.Signal)
 	signal.Notify(stop, os.Interrupt)
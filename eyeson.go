@@ -6,10 +6,10 @@ package eyeson
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -37,6 +37,15 @@ type Client struct {
 	Observer           *ObserverService
 	customCAFile       string
 	insecureSkipVerify bool
+	retryPolicy        *RetryPolicy
+	rateLimiter        *RateLimiter
+	middlewares        []Middleware
+	logger             Logger
+	instrumentation    Instrumentation
+
+	// transport is the transport below the middlewares, providing proxy and
+	// TLS configuration to the observer websocket.
+	transport http.RoundTripper
 }
 
 type service struct {
@@ -60,6 +69,32 @@ func WithInsecureSkipVerify() ClientOption {
 	}
 }
 
+// WithHTTPClient Set the HTTP client used to send requests instead of
+// http.DefaultClient. The options WithCustomCAFile and
+// WithInsecureSkipVerify replace the transport of a copy of this client. A nil
+// client keeps http.DefaultClient. The observer uses the proxy and TLS
+// configuration of the client's transport if it is an *http.Transport.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// Middleware wraps a http.RoundTripper to intercept requests and responses,
+// e.g. to add headers, proxies or tracing.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// WithMiddleware Add middlewares to the transport of the HTTP client. The
+// first given middleware is the outermost one, seeing requests first. User
+// clients created by Join or GuestJoin share the resulting transport.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
 // WithCustomEndpoint Set an endpoint which differs from the official
 // api endpoint.
 func WithCustomEndpoint(endpoint string) ClientOption {
@@ -99,12 +134,8 @@ func NewClient(key string, options ...ClientOption) (*Client, error) {
 			DisableCompression: true,
 			TLSClientConfig:    tlsConfig,
 		}
-		c.client = &http.Client{
-			Transport: tr,
-		}
-	}
-
-	if c.insecureSkipVerify {
+		c.client = withTransport(c.client, tr)
+	} else if c.insecureSkipVerify {
 		tlsConfig := &tls.Config{
 			InsecureSkipVerify: c.insecureSkipVerify,
 		}
@@ -114,9 +145,19 @@ func NewClient(key string, options ...ClientOption) (*Client, error) {
 			DisableCompression: true,
 			TLSClientConfig:    tlsConfig,
 		}
-		c.client = &http.Client{
-			Transport: tr,
+		c.client = withTransport(c.client, tr)
+	}
+
+	c.transport = c.client.Transport
+	if len(c.middlewares) > 0 {
+		tr := c.client.Transport
+		if tr == nil {
+			tr = http.DefaultTransport
 		}
+		for i := len(c.middlewares) - 1; i >= 0; i-- {
+			tr = c.middlewares[i](tr)
+		}
+		c.client = withTransport(c.client, tr)
 	}
 
 	c.Rooms = &RoomsService{c}
@@ -125,14 +166,30 @@ func NewClient(key string, options ...ClientOption) (*Client, error) {
 	return c, nil
 }
 
+// withTransport provides a copy of the HTTP client using the given transport,
+// leaving the original client untouched.
+func withTransport(httpClient *http.Client, tr http.RoundTripper) *http.Client {
+	copied := *httpClient
+	copied.Transport = tr
+	return &copied
+}
+
 // UserClient provides a client for user requests that use the session access
 // key for authorization.
 func (c *Client) UserClient() *Client {
-	return &Client{BaseURL: c.BaseURL, client: c.client}
+	return &Client{BaseURL: c.BaseURL, client: c.client, transport: c.transport,
+		retryPolicy: c.retryPolicy, rateLimiter: c.rateLimiter, logger: c.logger,
+		instrumentation: c.instrumentation}
 }
 
 // NewRequest prepares a request to be sent to the API.
 func (c *Client) NewRequest(method, urlStr string, data url.Values) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, urlStr, data)
+}
+
+// NewRequestWithContext prepares a request to be sent to the API. The given
+// context controls the entire lifetime of the request and its response.
+func (c *Client) NewRequestWithContext(ctx context.Context, method, urlStr string, data url.Values) (*http.Request, error) {
 	u := c.BaseURL.JoinPath(urlStr)
 	hasBody := method == http.MethodPost || method == http.MethodPut
 
@@ -146,7 +203,7 @@ func (c *Client) NewRequest(method, urlStr string, data url.Values) (*http.Reque
 		body = strings.NewReader(data.Encode())
 	}
 
-	req, err := http.NewRequest(method, u.String(), body)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -165,9 +222,16 @@ func (c *Client) NewRequest(method, urlStr string, data url.Values) (*http.Reque
 
 // NewPlainRequest create a request with bytes and content-type
 func (c *Client) NewPlainRequest(method, urlStr string, data *bytes.Buffer, contentType string) (*http.Request, error) {
+	return c.NewPlainRequestWithContext(context.Background(), method, urlStr, data, contentType)
+}
+
+// NewPlainRequestWithContext create a request with bytes and content-type
+// bound to the given context.
+func (c *Client) NewPlainRequestWithContext(ctx context.Context, method, urlStr string,
+	data *bytes.Buffer, contentType string) (*http.Request, error) {
 	u := c.BaseURL.JoinPath(urlStr)
 
-	req, err := http.NewRequest(method, u.String(), data)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), data)
 	if err != nil {
 		return nil, err
 	}
@@ -183,9 +247,20 @@ func (c *Client) NewPlainRequest(method, urlStr string, data *bytes.Buffer, cont
 }
 
 // Do sends a request to the eyeson API and prepares the result from the
-// received response.
+// received response. Cancellation and deadlines are taken from the context
+// of the request, see NewRequestWithContext. Failed requests are retried if
+// a retry policy is set, see WithRetryPolicy, and delayed or rejected by a
+// rate limiter, see WithRateLimiter.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+	req, finish := c.startOperation(req)
+	resp, err := c.do(req, v)
+	finish(resp, err)
+	return resp, err
+}
+
+// do sends the request and decodes the response into v.
+func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
+	resp, err := c.sendWithRetry(req)
 
 	if err != nil {
 		return nil, err
@@ -194,8 +269,10 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 
 	err = validateResponse(resp)
 	if err != nil {
-		// body, _ := ioutil.ReadAll(resp.Body)
-		// log.Printf("Received body: %s", body)
+		if apiErr, ok := err.(*APIError); ok {
+			// errors end up in logs, keep access keys out of them
+			apiErr.Path = c.redactPath(apiErr.Path)
+		}
 		return nil, err
 	}
 
@@ -208,18 +285,11 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	return resp, err
 }
 
+// validateResponse returns an *APIError for any unsuccessful response.
 func validateResponse(resp *http.Response) error {
 	c := resp.StatusCode
-	switch {
-	case c == 200 || c == 201 || c == 204:
+	if c == 200 || c == 201 || c == 204 {
 		return nil
-	case c == 404:
-		return errors.New("Not found! Resource does not exist or expired")
-	case c == 401:
-		return errors.New("Authorization failed! Check the API key to be valid")
-	case c == 403:
-		return errors.New("Bad request! Check your request parameters to be valid")
-	default:
-		return fmt.Errorf("Unknown error! Request failed for an unknown error (%d)", c)
 	}
+	return newAPIError(resp)
 }
@@ -1,6 +1,8 @@
 package eyeson
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -89,6 +91,90 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClient_withHTTPClient(t *testing.T) {
+	called := false
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	c, err := NewClient(client.apiKey, WithHTTPClient(httpClient), WithCustomEndpoint(serverURL))
+	if err != nil {
+		t.Fatalf("Failed to init client: %s", err)
+	}
+	if _, err = c.Rooms.GetCurrentMeetings(); err != nil {
+		t.Errorf("GetCurrentMeetings returned unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("Expected custom HTTP client to be used")
+	}
+}
+
+func TestNewClient_withNilHTTPClient(t *testing.T) {
+	client, mux, serverURL, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	c, err := NewClient(client.apiKey, WithHTTPClient(nil), WithCustomEndpoint(serverURL))
+	if err != nil {
+		t.Fatalf("Failed to init client: %s", err)
+	}
+	if _, err = c.Rooms.GetCurrentMeetings(); err != nil {
+		t.Errorf("GetCurrentMeetings returned unexpected error: %v", err)
+	}
+}
+
+func TestNewClient_withMiddleware(t *testing.T) {
+	_, mux, serverURL, teardown := setup()
+	defer teardown()
+
+	header := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				req = req.Clone(req.Context())
+				req.Header.Add("X-Middleware", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_key":"token"}`)
+	})
+	mux.HandleFunc("/rooms/token/messages", func(w http.ResponseWriter, r *http.Request) {
+		want := []string{"outer", "inner"}
+		if got := r.Header.Values("X-Middleware"); !reflect.DeepEqual(got, want) {
+			t.Errorf("Middleware headers are %v, want %v", got, want)
+		}
+		w.WriteHeader(201)
+	})
+
+	c, err := NewClient(testAPIKey, WithCustomEndpoint(serverURL),
+		WithMiddleware(header("outer"), header("inner")))
+	if err != nil {
+		t.Fatalf("Failed to init client: %s", err)
+	}
+	user, err := c.Rooms.Join("", "mike", nil)
+	if err != nil {
+		t.Fatalf("RoomsService Join not successfull, got %v", err)
+	}
+	if err = user.Chat("hello"); err != nil {
+		t.Errorf("Chat via user client returned unexpected error: %v", err)
+	}
+}
+
 func TestNewRequest_authorization(t *testing.T) {
 	apiKey := "secret-key"
 	c, err := NewClient(apiKey)
@@ -121,6 +207,38 @@ func TestNewRequest_userAuthorization(t *testing.T) {
 	}
 }
 
+func TestNewRequestWithContext(t *testing.T) {
+	c, err := NewClient("api-key")
+	if err != nil {
+		t.Fatalf("Failed to init client: %s", err)
+	}
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	req, err := c.NewRequestWithContext(ctx, "GET", ".", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext returned unexpected error: %v", err)
+	}
+	if got := req.Context().Value(ctxKey{}); got != "value" {
+		t.Fatalf("NewRequestWithContext context value is %v, want value", got)
+	}
+}
+
+func TestDo_canceledContext(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request should not reach the server")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := client.NewRequestWithContext(ctx, "GET", ".", nil)
+	if _, err := client.Do(req, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled error, got %v", err)
+	}
+}
+
 func TestNewRequest_userAgent(t *testing.T) {
 	c, err := NewClient("")
 	if err != nil {
@@ -166,7 +284,6 @@ func TestDo_httpError(t *testing.T) {
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Bad Request", 400)
 	})
-	return
 
 	req, _ := client.NewRequest("GET", ".", nil)
 	resp, err := client.Do(req, nil)
@@ -174,6 +291,9 @@ func TestDo_httpError(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected HTTP 400 error, got no error.")
 	}
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest, got %v", err)
+	}
 	if resp != nil {
 		t.Errorf("Expected empty response, got %v", resp.Body)
 	}
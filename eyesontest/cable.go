@@ -0,0 +1,181 @@
+package eyesontest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// PingInterval is the interval of ActionCable pings sent to observers.
+var PingInterval = 3 * time.Second
+
+const roomChannel = "RoomChannel"
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// cableMessage is a message sent from the server to an ActionCable client.
+type cableMessage struct {
+	Type       string          `json:"type,omitempty"`
+	Identifier string          `json:"identifier,omitempty"`
+	Message    json.RawMessage `json:"message,omitempty"`
+}
+
+// cableCommand is a command sent from an ActionCable client to the server.
+type cableCommand struct {
+	Command    string `json:"command"`
+	Identifier string `json:"identifier"`
+}
+
+// subscriber is an observer connection subscribed to the room channel.
+type subscriber struct {
+	identifier string
+	messages   chan []byte
+	done       chan struct{}
+	once       sync.Once
+}
+
+func (sub *subscriber) close() {
+	sub.once.Do(func() { close(sub.done) })
+}
+
+// publish sends an event to all observers of the room. Slow observers miss
+// events. Must be called with the lock held.
+func (s *Server) publish(roomID string, event interface{}) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	for sub := range s.subscribers[roomID] {
+		select {
+		case sub.messages <- raw:
+		default:
+		}
+	}
+	return nil
+}
+
+// Disconnect closes all observer connections of the room, e.g. to test
+// reconnects.
+func (s *Server) Disconnect(roomID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers[roomID] {
+		sub.close()
+	}
+}
+
+// Observers provides the number of observers subscribed to the room.
+func (s *Server) Observers(roomID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers[roomID])
+}
+
+// serveCable handles the ActionCable websocket used by the observer.
+func (s *Server) serveCable(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+	roomID := r.URL.Query().Get("room_id")
+	s.mu.Lock()
+	room, ok := s.rooms[roomID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "room not found")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := &subscriber{messages: make(chan []byte, 64), done: make(chan struct{})}
+	commands := make(chan cableCommand)
+	go func() {
+		defer sub.close()
+		for {
+			var cmd cableCommand
+			if err := conn.ReadJSON(&cmd); err != nil {
+				return
+			}
+			select {
+			case commands <- cmd:
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers[room.ID], sub)
+		s.mu.Unlock()
+	}()
+
+	if err := conn.WriteJSON(cableMessage{Type: "welcome"}); err != nil {
+		return
+	}
+	ping := time.NewTicker(PingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-ping.C:
+			ts, _ := json.Marshal(time.Now().Unix())
+			if err := conn.WriteJSON(cableMessage{Type: "ping", Message: ts}); err != nil {
+				return
+			}
+		case cmd := <-commands:
+			if !s.handleCommand(conn, sub, room.ID, cmd) {
+				return
+			}
+		case raw := <-sub.messages:
+			msg := cableMessage{Identifier: sub.identifier, Message: raw}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleCommand processes a command of an ActionCable client and reports
+// whether the connection should be kept.
+func (s *Server) handleCommand(conn *websocket.Conn, sub *subscriber, roomID string, cmd cableCommand) bool {
+	var identifier struct {
+		Channel string `json:"channel"`
+	}
+	json.Unmarshal([]byte(cmd.Identifier), &identifier)
+	switch cmd.Command {
+	case "subscribe":
+		if identifier.Channel != roomChannel {
+			return conn.WriteJSON(cableMessage{Type: "reject_subscription", Identifier: cmd.Identifier}) == nil
+		}
+		sub.identifier = cmd.Identifier
+		if err := conn.WriteJSON(cableMessage{Type: "confirm_subscription", Identifier: cmd.Identifier}); err != nil {
+			return false
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.subscribers[roomID] == nil {
+			s.subscribers[roomID] = map[*subscriber]bool{}
+		}
+		s.subscribers[roomID][sub] = true
+		raw, err := json.Marshal(roomUpdate(s.rooms[roomID]))
+		if err != nil {
+			return false
+		}
+		return conn.WriteJSON(cableMessage{Identifier: sub.identifier, Message: raw}) == nil
+	case "unsubscribe":
+		s.mu.Lock()
+		delete(s.subscribers[roomID], sub)
+		s.mu.Unlock()
+	}
+	return true
+}
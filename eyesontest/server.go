@@ -0,0 +1,905 @@
+// Package eyesontest provides a stateful in-memory fake of the eyeson API for
+// integration tests. It covers rooms, users, guests, messages, layouts,
+// layers, recordings, snapshots, playbacks, broadcasts, forwards, webhooks
+// and locking, as well as the ActionCable endpoint used by the observer.
+//
+//	fake := eyesontest.NewServer("api-key")
+//	defer fake.Close()
+//	client, _ := eyeson.NewClient("api-key", eyeson.WithCustomEndpoint(fake.URL))
+package eyesontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+)
+
+// Room is the state of a room held by the fake server.
+type Room struct {
+	ID         string
+	Name       string
+	GuestToken string
+	StartedAt  time.Time
+	Shutdown   bool
+	Locked     bool
+	// Options holds the raw options[...] form values given on start.
+	Options    map[string]string
+	Users      []User
+	Layout     Layout
+	Layers     map[int]Layer
+	Playbacks  []eyeson.Playback
+	Broadcasts []eyeson.Broadcast
+	Forwards   []Forward
+	// Recording is the active recording, if any.
+	Recording *eyeson.Recording
+	Messages  []Message
+}
+
+// User is a participant of a room.
+type User struct {
+	ID        string
+	Name      string
+	Avatar    string
+	AccessKey string
+	Guest     bool
+	Online    bool
+	JoinedAt  time.Time
+}
+
+// Layout is the podium layout last set for a room.
+type Layout struct {
+	Layout          string
+	Name            string
+	Users           []string
+	VoiceActivation bool
+	ShowNames       *bool
+	Map             string
+	AudioInsert     string
+}
+
+// Layer is an image set as fore- or background of a room.
+type Layer struct {
+	ZIndex int
+	ID     string
+	URL    string
+	Image  []byte
+}
+
+// Forward is a media forward of a room.
+type Forward struct {
+	ID     string
+	UserID string
+	URL    string
+	Types  []string
+}
+
+// Message is a chat or custom message sent to a room.
+type Message struct {
+	Type      string
+	Content   string
+	UserID    string
+	CreatedAt time.Time
+}
+
+// Server is a fake eyeson API server.
+type Server struct {
+	// URL is the base URL of the server, to be used with
+	// eyeson.WithCustomEndpoint.
+	URL string
+	// APIKey is the key expected in the Authorization header.
+	APIKey string
+
+	server *httptest.Server
+
+	mu          sync.Mutex
+	seq         int
+	rooms       map[string]*Room
+	users       map[string]string // access key to room id
+	guests      map[string]string // guest token to room id
+	recordings  map[string]*eyeson.Recording
+	snapshots   map[string]*eyeson.Snapshot
+	webhooks    []eyeson.WebhookDetails
+	failures    []int
+	subscribers map[string]map[*subscriber]bool
+}
+
+// NewServer starts a new fake server accepting the given API key.
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		APIKey:      apiKey,
+		rooms:       map[string]*Room{},
+		users:       map[string]string{},
+		guests:      map[string]string{},
+		recordings:  map[string]*eyeson.Recording{},
+		snapshots:   map[string]*eyeson.Snapshot{},
+		subscribers: map[string]map[*subscriber]bool{},
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server and all observer connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for _, subs := range s.subscribers {
+		for sub := range subs {
+			sub.close()
+		}
+	}
+	s.mu.Unlock()
+	s.server.CloseClientConnections()
+	s.server.Close()
+}
+
+// Room provides a copy of the state of the room with the given identifier.
+func (s *Server) Room(id string) (Room, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room, ok := s.rooms[id]
+	if !ok {
+		return Room{}, false
+	}
+	return room.clone(), true
+}
+
+// Rooms provides a copy of the state of all rooms, ordered by identifier.
+func (s *Server) Rooms() []Room {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rooms := make([]Room, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room.clone())
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].ID < rooms[j].ID })
+	return rooms
+}
+
+// Webhooks provides all registered webhooks.
+func (s *Server) Webhooks() []eyeson.WebhookDetails {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]eyeson.WebhookDetails(nil), s.webhooks...)
+}
+
+// FailRequests lets the next requests fail with the given status codes, one
+// status code per request.
+func (s *Server) FailRequests(statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statusCodes...)
+}
+
+// SendEvent sends an event to all observers of the room.
+func (s *Server) SendEvent(roomID string, event eyeson.EventInterface) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.publish(roomID, event)
+}
+
+func (r *Room) clone() Room {
+	c := *r
+	c.Options = map[string]string{}
+	for k, v := range r.Options {
+		c.Options[k] = v
+	}
+	c.Users = append([]User(nil), r.Users...)
+	c.Layout.Users = append([]string(nil), r.Layout.Users...)
+	c.Layers = map[int]Layer{}
+	for k, v := range r.Layers {
+		c.Layers[k] = v
+	}
+	c.Playbacks = append([]eyeson.Playback(nil), r.Playbacks...)
+	c.Broadcasts = append([]eyeson.Broadcast(nil), r.Broadcasts...)
+	c.Forwards = append([]Forward(nil), r.Forwards...)
+	if r.Recording != nil {
+		recording := *r.Recording
+		c.Recording = &recording
+	}
+	c.Messages = append([]Message(nil), r.Messages...)
+	return c
+}
+
+func (r *Room) user(accessKey string) *User {
+	for i := range r.Users {
+		if r.Users[i].AccessKey == accessKey {
+			return &r.Users[i]
+		}
+	}
+	return nil
+}
+
+// nextID provides a new unique identifier. Must be called with the lock
+// held.
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%d", prefix, s.seq)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// ServeHTTP implements http.Handler and routes all API requests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		s.mu.Unlock()
+		writeError(w, status, http.StatusText(status))
+		return
+	}
+	s.mu.Unlock()
+
+	segments := []string{}
+	for _, segment := range strings.Split(r.URL.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			r.ParseMultipartForm(32 << 20)
+		} else {
+			r.ParseForm()
+		}
+	}
+
+	switch segments[0] {
+	case "rt":
+		s.serveCable(w, r)
+	case "rooms":
+		if len(segments) == 1 {
+			s.serveRooms(w, r)
+			return
+		}
+		s.serveRoom(w, r, segments[1], segments[2:])
+	case "guests":
+		if len(segments) == 2 && r.Method == http.MethodPost {
+			s.guestJoin(w, r, segments[1])
+			return
+		}
+		writeError(w, http.StatusNotFound, "not found")
+	case "recordings", "snapshots":
+		if !s.authorized(w, r) {
+			return
+		}
+		if len(segments) != 2 {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		s.serveMedia(w, r, segments[0], segments[1])
+	case "webhooks":
+		if !s.authorized(w, r) {
+			return
+		}
+		s.serveWebhooks(w, r, segments[1:])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// authorized checks the API key of the request.
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if s.APIKey == "" || r.Header.Get("Authorization") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "invalid api key")
+		return false
+	}
+	return true
+}
+
+func (s *Server) serveRooms(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		s.join(w, r)
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		infos := []eyeson.RoomInfo{}
+		for _, room := range s.rooms {
+			if room.Shutdown {
+				continue
+			}
+			infos = append(infos, eyeson.RoomInfo{ID: room.ID, Name: room.Name, Ready: true,
+				StartedAt: room.StartedAt.Format(time.RFC3339), GuestToken: room.GuestToken})
+		}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+		writeJSON(w, http.StatusOK, infos)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// startRoom provides a running room for the given identifier, starting a new
+// one if required. Must be called with the lock held.
+func (s *Server) startRoom(id, name string, options url.Values) *Room {
+	if id == "" {
+		id = s.nextID("room")
+	}
+	room, ok := s.rooms[id]
+	if ok && !room.Shutdown {
+		return room
+	}
+	if name == "" {
+		name = id
+	}
+	room = &Room{
+		ID:         id,
+		Name:       name,
+		GuestToken: s.nextID("guest"),
+		StartedAt:  time.Now().UTC(),
+		Options:    map[string]string{},
+		Layers:     map[int]Layer{},
+	}
+	for k := range options {
+		if strings.HasPrefix(k, "options[") {
+			room.Options[k] = options.Get(k)
+		}
+	}
+	s.rooms[id] = room
+	s.guests[room.GuestToken] = id
+	return room
+}
+
+// addUser adds a new online user to the room. Must be called with the lock
+// held.
+func (s *Server) addUser(room *Room, id, name, avatar string, guest bool) *User {
+	if id == "" {
+		id = s.nextID("user")
+	}
+	room.Users = append(room.Users, User{
+		ID:        id,
+		Name:      name,
+		Avatar:    avatar,
+		AccessKey: s.nextID("access-key"),
+		Guest:     guest,
+		Online:    true,
+		JoinedAt:  time.Now().UTC(),
+	})
+	user := &room.Users[len(room.Users)-1]
+	s.users[user.AccessKey] = room.ID
+	s.publish(room.ID, &eyeson.ParticipantUpdate{
+		EventBase:   eyeson.EventBase{Type: "participant_update"},
+		Participant: participant(room, user),
+	})
+	return user
+}
+
+func (s *Server) join(w http.ResponseWriter, r *http.Request) {
+	name := r.Form.Get("user[name]")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "user name is missing")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := s.startRoom(r.Form.Get("id"), r.Form.Get("name"), r.Form)
+	if room.Locked {
+		writeError(w, http.StatusForbidden, "meeting is locked")
+		return
+	}
+	user := s.addUser(room, r.Form.Get("user[id]"), name, r.Form.Get("user[avatar]"), false)
+	s.publish(room.ID, roomUpdate(room))
+	writeJSON(w, http.StatusCreated, s.roomResponse(room, user))
+}
+
+func (s *Server) guestJoin(w http.ResponseWriter, r *http.Request, guestToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room, ok := s.rooms[s.guests[guestToken]]
+	if !ok || room.Shutdown {
+		writeError(w, http.StatusNotFound, "room not found")
+		return
+	}
+	if room.Locked {
+		writeError(w, http.StatusForbidden, "meeting is locked")
+		return
+	}
+	name := r.Form.Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name is missing")
+		return
+	}
+	user := s.addUser(room, r.Form.Get("id"), name, r.Form.Get("avatar"), true)
+	writeJSON(w, http.StatusCreated, s.roomResponse(room, user))
+}
+
+func (s *Server) roomResponse(room *Room, user *User) *eyeson.RoomResponse {
+	base := strings.Replace(s.URL, "http", "ws", 1)
+	return &eyeson.RoomResponse{
+		AccessKey: user.AccessKey,
+		Links: eyeson.RoomLinks{
+			Gui:       s.URL + "/?" + user.AccessKey,
+			GuestJoin: s.URL + "/guest?" + room.GuestToken,
+			Websocket: base + "/rt?access_key=" + user.AccessKey,
+		},
+		Room: eyeson.Room{
+			ID:         room.ID,
+			GuestToken: room.GuestToken,
+			Shutdown:   room.Shutdown,
+		},
+		User:  eyeson.User{ID: user.ID, Name: user.Name},
+		Ready: !room.Shutdown,
+		Options: eyeson.RoomOptions{
+			Widescreen: room.Options["options[widescreen]"] == "true",
+		},
+	}
+}
+
+// serveRoom handles all routes below /rooms/{key}, where key is either an
+// access key or a room identifier for requests authorized by API key.
+func (s *Server) serveRoom(w http.ResponseWriter, r *http.Request, key string, rest []string) {
+	s.mu.Lock()
+	roomID, isUser := s.users[key]
+	s.mu.Unlock()
+	if !isUser {
+		if !s.authorized(w, r) {
+			return
+		}
+		s.serveRoomByID(w, r, key, rest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := s.rooms[roomID]
+	user := room.user(key)
+	if user == nil {
+		// the room has been restarted, old access keys are expired
+		writeError(w, http.StatusNotFound, "room expired")
+		return
+	}
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.roomResponse(room, user))
+		case http.MethodDelete:
+			s.shutdown(room)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	if room.Shutdown {
+		writeError(w, http.StatusNotFound, "room expired")
+		return
+	}
+
+	route := r.Method + " " + rest[0]
+	switch {
+	case route == "POST messages":
+		s.message(w, r, room, user)
+	case route == "POST recording":
+		s.startRecording(w, room, user)
+	case route == "DELETE recording":
+		s.stopRecording(w, room)
+	case route == "POST broadcasts":
+		s.startBroadcast(w, r, room, user)
+	case route == "DELETE broadcasts":
+		room.Broadcasts = nil
+		s.publish(room.ID, &eyeson.BroadcastUpdate{EventBase: eyeson.EventBase{Type: "broadcasts_update"},
+			Broadcasts: []eyeson.Broadcast{}})
+		w.WriteHeader(http.StatusOK)
+	case route == "POST layout":
+		s.setLayout(w, r, room)
+	case route == "POST layers":
+		s.setLayer(w, r, room)
+	case route == "DELETE layers" && len(rest) == 2:
+		zIndex, _ := strconv.Atoi(rest[1])
+		delete(room.Layers, zIndex)
+		w.WriteHeader(http.StatusOK)
+	case route == "POST playbacks":
+		s.startPlayback(w, r, room)
+	case route == "DELETE playbacks" && len(rest) == 2:
+		s.stopPlayback(w, room, rest[1])
+	case route == "POST snapshot":
+		s.createSnapshot(w, room, user)
+	case route == "GET snapshots" && len(rest) == 2:
+		snapshot, ok := s.snapshots[rest[1]]
+		if !ok || snapshot.Room.ID != room.ID {
+			writeError(w, http.StatusNotFound, "snapshot not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, snapshot)
+	case route == "POST lock":
+		room.Locked = true
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// serveRoomByID handles the routes below /rooms/{id} authorized by API key.
+func (s *Server) serveRoomByID(w http.ResponseWriter, r *http.Request, id string, rest []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room, ok := s.rooms[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "room not found")
+		return
+	}
+	route := r.Method
+	if len(rest) > 0 {
+		route += " " + rest[0]
+	}
+	switch {
+	case route == "GET":
+		writeJSON(w, http.StatusOK, eyeson.RoomInfo{ID: room.ID, Name: room.Name, Ready: !room.Shutdown,
+			StartedAt: room.StartedAt.Format(time.RFC3339), Shutdown: room.Shutdown,
+			GuestToken: room.GuestToken})
+	case route == "DELETE":
+		s.shutdown(room)
+		w.WriteHeader(http.StatusNoContent)
+	case route == "GET users":
+		users := []eyeson.Participant{}
+		online := r.URL.Query().Get("online")
+		for i := range room.Users {
+			if online != "" && strconv.FormatBool(room.Users[i].Online) != online {
+				continue
+			}
+			users = append(users, participant(room, &room.Users[i]))
+		}
+		writeJSON(w, http.StatusOK, users)
+	case route == "GET recordings":
+		recordings := []eyeson.Recording{}
+		for _, recording := range s.recordings {
+			if recording.Room.ID == room.ID {
+				recordings = append(recordings, *recording)
+			}
+		}
+		sort.Slice(recordings, func(i, j int) bool { return recordings[i].ID < recordings[j].ID })
+		writeJSON(w, http.StatusOK, recordings)
+	case route == "GET snapshots":
+		writeJSON(w, http.StatusOK, s.roomSnapshots(room))
+	case route == "POST forward" && len(rest) == 2 && rest[1] == "source":
+		forward := Forward{
+			ID:     r.Form.Get("forward_id"),
+			UserID: r.Form.Get("user_id"),
+			URL:    r.Form.Get("url"),
+			Types:  strings.Split(r.Form.Get("type"), ","),
+		}
+		if forward.ID == "" || forward.URL == "" {
+			writeError(w, http.StatusBadRequest, "forward id and url are required")
+			return
+		}
+		room.Forwards = append(room.Forwards, forward)
+		w.WriteHeader(http.StatusCreated)
+	case route == "DELETE forward" && len(rest) == 2:
+		for i, forward := range room.Forwards {
+			if forward.ID == rest[1] {
+				room.Forwards = append(room.Forwards[:i], room.Forwards[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "forward not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// shutdown stops a meeting. Must be called with the lock held.
+func (s *Server) shutdown(room *Room) {
+	if room.Shutdown {
+		return
+	}
+	if room.Recording != nil {
+		s.finishRecording(room)
+	}
+	room.Shutdown = true
+	for i := range room.Users {
+		room.Users[i].Online = false
+	}
+	room.Playbacks = nil
+	room.Broadcasts = nil
+	s.publish(room.ID, roomUpdate(room))
+}
+
+func (s *Server) message(w http.ResponseWriter, r *http.Request, room *Room, user *User) {
+	msgType := r.Form.Get("type")
+	if msgType != "chat" && msgType != "custom" {
+		writeError(w, http.StatusBadRequest, "invalid message type")
+		return
+	}
+	msg := Message{Type: msgType, Content: r.Form.Get("content"), UserID: user.ID,
+		CreatedAt: time.Now().UTC()}
+	room.Messages = append(room.Messages, msg)
+	if msgType == "chat" {
+		s.publish(room.ID, &eyeson.Chat{EventBase: eyeson.EventBase{Type: "chat"},
+			Content: msg.Content, ClientID: s.nextID("cid"), UserID: msg.UserID, CreatedAt: msg.CreatedAt})
+	} else {
+		s.publish(room.ID, &eyeson.CustomMessage{EventBase: eyeson.EventBase{Type: "custom"},
+			Content: msg.Content, ClientID: s.nextID("cid"), UserID: msg.UserID, CreatedAt: msg.CreatedAt})
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) startRecording(w http.ResponseWriter, room *Room, user *User) {
+	if room.Recording != nil {
+		writeError(w, http.StatusConflict, "recording already active")
+		return
+	}
+	room.Recording = &eyeson.Recording{
+		ID:        s.nextID("recording"),
+		CreatedAt: int(time.Now().Unix()),
+		User:      eventUser(user),
+		Room:      eventRoom(room),
+	}
+	s.publish(room.ID, &eyeson.RecordingUpdate{EventBase: eyeson.EventBase{Type: "recording_update"},
+		Recording: *room.Recording})
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) stopRecording(w http.ResponseWriter, room *Room) {
+	if room.Recording == nil {
+		writeError(w, http.StatusNotFound, "no active recording")
+		return
+	}
+	s.finishRecording(room)
+	w.WriteHeader(http.StatusOK)
+}
+
+// finishRecording stops the active recording and stores it. Must be called
+// with the lock held.
+func (s *Server) finishRecording(room *Room) {
+	recording := room.Recording
+	room.Recording = nil
+	duration := int(time.Now().Unix()) - recording.CreatedAt
+	self := s.URL + "/recordings/" + recording.ID
+	download := s.URL + "/downloads/" + recording.ID + ".webm"
+	recording.Duration = &duration
+	recording.Links = eyeson.Links{Self: &self, Download: &download}
+	s.recordings[recording.ID] = recording
+	s.publish(room.ID, &eyeson.RecordingUpdate{EventBase: eyeson.EventBase{Type: "recording_update"},
+		Recording: *recording})
+}
+
+func (s *Server) startBroadcast(w http.ResponseWriter, r *http.Request, room *Room, user *User) {
+	streamURL := r.Form.Get("stream_url")
+	if streamURL == "" {
+		writeError(w, http.StatusBadRequest, "stream url is missing")
+		return
+	}
+	room.Broadcasts = append(room.Broadcasts, eyeson.Broadcast{
+		ID:        s.nextID("broadcast"),
+		Platform:  "generic",
+		PlayerURL: streamURL,
+		User:      eventUser(user),
+		Room:      eventRoom(room),
+	})
+	s.publish(room.ID, &eyeson.BroadcastUpdate{EventBase: eyeson.EventBase{Type: "broadcasts_update"},
+		Broadcasts: append([]eyeson.Broadcast(nil), room.Broadcasts...)})
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) setLayout(w http.ResponseWriter, r *http.Request, room *Room) {
+	layout := Layout{
+		Layout:          r.Form.Get("layout"),
+		Name:            r.Form.Get("name"),
+		Users:           r.Form["users[]"],
+		VoiceActivation: r.Form.Get("voice_activation") == "true",
+		Map:             r.Form.Get("map"),
+		AudioInsert:     r.Form.Get("audio_insert"),
+	}
+	if showNames := r.Form.Get("show_names"); showNames != "" {
+		v := showNames == "true"
+		layout.ShowNames = &v
+	}
+	room.Layout = layout
+
+	users := layout.Users
+	if layout.Layout != "custom" {
+		users = nil
+		for _, user := range room.Users {
+			if user.Online {
+				users = append(users, user.ID)
+			}
+		}
+	}
+	podium := []eyeson.PodiumPosition{}
+	for i, userID := range users {
+		if userID == "" {
+			continue
+		}
+		podium = append(podium, eyeson.PodiumPosition{UserID: userID, Width: 640, Height: 360,
+			Left: (i % 2) * 640, Top: (i / 2) * 360})
+	}
+	s.publish(room.ID, &eyeson.PodiumUpdate{EventBase: eyeson.EventBase{Type: "podium_update"},
+		Podium: podium})
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setLayer(w http.ResponseWriter, r *http.Request, room *Room) {
+	zIndex, err := strconv.Atoi(r.FormValue("z-index"))
+	if err != nil {
+		zIndex = 1
+	}
+	layer := Layer{ZIndex: zIndex, ID: r.FormValue("id"), URL: r.FormValue("url")}
+	if r.MultipartForm != nil {
+		if files := r.MultipartForm.File["file"]; len(files) > 0 {
+			if f, err := files[0].Open(); err == nil {
+				layer.Image = make([]byte, files[0].Size)
+				f.Read(layer.Image)
+				f.Close()
+			}
+		}
+	}
+	if layer.URL == "" && layer.Image == nil {
+		writeError(w, http.StatusBadRequest, "url or file is required")
+		return
+	}
+	room.Layers[zIndex] = layer
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) startPlayback(w http.ResponseWriter, r *http.Request, room *Room) {
+	playbackURL := r.Form.Get("playback[url]")
+	if playbackURL == "" {
+		writeError(w, http.StatusBadRequest, "playback url is missing")
+		return
+	}
+	playID := r.Form.Get("playback[play_id]")
+	if playID == "" {
+		playID = s.nextID("playback")
+	}
+	room.Playbacks = append(room.Playbacks, eyeson.Playback{URL: playbackURL, PlayID: playID,
+		Audio: r.Form.Get("playback[audio]") == "true"})
+	s.publishPlaybacks(room)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) stopPlayback(w http.ResponseWriter, room *Room, playID string) {
+	for i, playback := range room.Playbacks {
+		if playback.PlayID == playID {
+			room.Playbacks = append(room.Playbacks[:i], room.Playbacks[i+1:]...)
+			s.publishPlaybacks(room)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "playback not found")
+}
+
+func (s *Server) publishPlaybacks(room *Room) {
+	s.publish(room.ID, &eyeson.PlaybackUpdate{EventBase: eyeson.EventBase{Type: "playback_update"},
+		Playing: append([]eyeson.Playback{}, room.Playbacks...)})
+}
+
+func (s *Server) createSnapshot(w http.ResponseWriter, room *Room, user *User) {
+	id := s.nextID("snapshot")
+	download := s.URL + "/downloads/" + id + ".jpg"
+	s.snapshots[id] = &eyeson.Snapshot{
+		ID:        id,
+		Name:      id,
+		Links:     eyeson.Links{Download: &download},
+		Creator:   eventUser(user),
+		CreatedAt: time.Now().UTC(),
+		Room:      eventRoom(room),
+	}
+	s.publish(room.ID, &eyeson.SnapshotUpdate{EventBase: eyeson.EventBase{Type: "snapshot_update"},
+		Snapshots: s.roomSnapshots(room)})
+	w.WriteHeader(http.StatusCreated)
+}
+
+// roomSnapshots provides all snapshots of a room. Must be called with the
+// lock held.
+func (s *Server) roomSnapshots(room *Room) []eyeson.Snapshot {
+	snapshots := []eyeson.Snapshot{}
+	for _, snapshot := range s.snapshots {
+		if snapshot.Room.ID == room.ID {
+			snapshots = append(snapshots, *snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots
+}
+
+// serveMedia handles the /recordings/{id} and /snapshots/{id} routes.
+func (s *Server) serveMedia(w http.ResponseWriter, r *http.Request, kind, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var item interface{}
+	var ok bool
+	if kind == "recordings" {
+		item, ok = s.recordings[id]
+	} else {
+		item, ok = s.snapshots[id]
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, item)
+	case http.MethodDelete:
+		delete(s.recordings, id)
+		delete(s.snapshots, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// serveWebhooks handles the /webhooks routes.
+func (s *Server) serveWebhooks(w http.ResponseWriter, r *http.Request, rest []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		endpoint := r.Form.Get("url")
+		if endpoint == "" || r.Form.Get("types") == "" {
+			writeError(w, http.StatusBadRequest, "url and types are required")
+			return
+		}
+		s.webhooks = append(s.webhooks, eyeson.WebhookDetails{
+			Id:    s.nextID("webhook"),
+			Url:   endpoint,
+			Types: strings.Split(r.Form.Get("types"), ","),
+		})
+		w.WriteHeader(http.StatusCreated)
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, append([]eyeson.WebhookDetails{}, s.webhooks...))
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		for i, webhook := range s.webhooks {
+			if webhook.Id == rest[0] {
+				s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "webhook not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func participant(room *Room, user *User) eyeson.Participant {
+	return eyeson.Participant{ID: user.ID, RoomID: room.ID, Name: user.Name, Guest: user.Guest,
+		Online: user.Online, Avatar: user.Avatar}
+}
+
+func eventUser(user *User) eyeson.EventUser {
+	return eyeson.EventUser{ID: user.ID, Name: user.Name, Guest: user.Guest, Avatar: user.Avatar,
+		JoinedAt: user.JoinedAt}
+}
+
+func eventRoom(room *Room) eyeson.EventRoom {
+	return eyeson.EventRoom{ID: room.ID, Name: room.Name, Ready: !room.Shutdown,
+		StartedAt: room.StartedAt, Shutdown: room.Shutdown, GuestToken: room.GuestToken}
+}
+
+func roomUpdate(room *Room) *eyeson.RoomUpdate {
+	content := eventRoom(room)
+	for i := range room.Users {
+		content.Participants = append(content.Participants, participant(room, &room.Users[i]))
+	}
+	content.Broadcasts = append([]eyeson.Broadcast{}, room.Broadcasts...)
+	return &eyeson.RoomUpdate{EventBase: eyeson.EventBase{Type: "room_update"}, Content: content}
+}
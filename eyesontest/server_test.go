@@ -0,0 +1,200 @@
+package eyesontest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"github.com/eyeson-team/eyeson-go/eyesontest"
+)
+
+const apiKey = "fake-api-key"
+
+func newClient(t *testing.T, fake *eyesontest.Server) *eyeson.Client {
+	client, err := eyeson.NewClient(apiKey, eyeson.WithCustomEndpoint(fake.URL))
+	if err != nil {
+		t.Fatalf("Failed to init client: %s", err)
+	}
+	return client
+}
+
+func TestServer_meeting(t *testing.T) {
+	fake := eyesontest.NewServer(apiKey)
+	defer fake.Close()
+	client := newClient(t, fake)
+
+	user, err := client.Rooms.Join("standup", "mike", map[string]string{"options[widescreen]": "true"})
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if err = user.WaitReady(); err != nil {
+		t.Fatalf("WaitReady failed: %v", err)
+	}
+	if !user.Data.Options.Widescreen {
+		t.Error("Expected widescreen option to be set")
+	}
+	guest, err := client.Rooms.GuestJoin(user.Data.Room.GuestToken, "", "guest", "")
+	if err != nil {
+		t.Fatalf("GuestJoin failed: %v", err)
+	}
+	if err = guest.Chat("hello"); err != nil {
+		t.Errorf("Chat failed: %v", err)
+	}
+	if err = user.SetLayout(eyeson.Custom, &eyeson.SetLayoutOptions{Users: []string{guest.Data.User.ID}}); err != nil {
+		t.Errorf("SetLayout failed: %v", err)
+	}
+	if err = user.SetLayer("https://example.com/overlay.png", eyeson.Foreground, nil); err != nil {
+		t.Errorf("SetLayer failed: %v", err)
+	}
+	if err = user.StartRecording(); err != nil {
+		t.Errorf("StartRecording failed: %v", err)
+	}
+	if err = user.StartRecording(); !errors.Is(err, eyeson.ErrConflict) {
+		t.Errorf("Expected second StartRecording to conflict, got %v", err)
+	}
+
+	room, ok := fake.Room("standup")
+	if !ok {
+		t.Fatal("Expected room standup to exist")
+	}
+	if len(room.Users) != 2 || !room.Users[1].Guest {
+		t.Errorf("Expected user and guest in room, got %+v", room.Users)
+	}
+	if len(room.Messages) != 1 || room.Messages[0].Content != "hello" {
+		t.Errorf("Expected chat message, got %+v", room.Messages)
+	}
+	if room.Layout.Layout != "custom" || len(room.Layout.Users) != 1 {
+		t.Errorf("Expected custom layout, got %+v", room.Layout)
+	}
+	if room.Layers[1].URL != "https://example.com/overlay.png" {
+		t.Errorf("Expected foreground layer, got %+v", room.Layers)
+	}
+	if room.Recording == nil {
+		t.Error("Expected active recording")
+	}
+
+	if err = user.StopMeeting(); err != nil {
+		t.Errorf("StopMeeting failed: %v", err)
+	}
+	recordings, err := client.Rooms.GetRecordings("standup", nil)
+	if err != nil || len(*recordings) != 1 {
+		t.Errorf("Expected one finished recording, got %v (%v)", recordings, err)
+	}
+	if err = user.Chat("too late"); !errors.Is(err, eyeson.ErrNotFound) {
+		t.Errorf("Expected chat after shutdown to fail, got %v", err)
+	}
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	fake := eyesontest.NewServer(apiKey)
+	defer fake.Close()
+	client, _ := eyeson.NewClient("wrong-key", eyeson.WithCustomEndpoint(fake.URL))
+
+	if _, err := client.Rooms.Join("", "mike", nil); !errors.Is(err, eyeson.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestServer_failRequests(t *testing.T) {
+	fake := eyesontest.NewServer(apiKey)
+	defer fake.Close()
+	client := newClient(t, fake)
+
+	fake.FailRequests(503)
+	if _, err := client.Rooms.GetCurrentMeetings(); !errors.Is(err, eyeson.ErrServer) {
+		t.Errorf("Expected ErrServer, got %v", err)
+	}
+	if _, err := client.Rooms.GetCurrentMeetings(); err != nil {
+		t.Errorf("Expected second request to succeed, got %v", err)
+	}
+}
+
+func TestServer_observer(t *testing.T) {
+	fake := eyesontest.NewServer(apiKey)
+	defer fake.Close()
+	client := newClient(t, fake)
+
+	user, err := client.Rooms.Join("observed", "mike", nil)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.Observer.Connect(ctx, "observed")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	next := func() eyeson.EventInterface {
+		select {
+		case ev := <-events:
+			return ev
+		case <-ctx.Done():
+			t.Fatal("Timeout waiting for observer event")
+		}
+		return nil
+	}
+	if ev, ok := next().(*eyeson.ConnectionState); !ok || ev.State != eyeson.ConnectionConnected {
+		t.Fatalf("Expected connected state, got %#v", ev)
+	}
+	if ev, ok := next().(*eyeson.RoomUpdate); !ok || ev.Content.ID != "observed" {
+		t.Fatalf("Expected initial room update, got %#v", ev)
+	}
+	for fake.Observers("observed") == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err = user.Chat("hello observer"); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if ev, ok := next().(*eyeson.Chat); !ok || ev.Content != "hello observer" || ev.UserID != user.Data.User.ID {
+		t.Errorf("Expected chat event, got %#v", ev)
+	}
+}
+
+func TestServer_webhooks(t *testing.T) {
+	fake := eyesontest.NewServer(apiKey)
+	defer fake.Close()
+	client := newClient(t, fake)
+
+	if err := client.Webhook.Register("https://example.com/a", eyeson.WEBHOOK_ROOM); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := client.Webhook.RegisterTypes("https://example.com/b", eyeson.WebhookSnapshotUpdate); err != nil {
+		t.Fatalf("RegisterTypes failed: %v", err)
+	}
+	webhooks, err := client.Webhook.List()
+	if err != nil || len(webhooks) != 2 {
+		t.Fatalf("Expected two webhooks, got %v, %v", webhooks, err)
+	}
+
+	kept, err := client.Webhook.Ensure("https://example.com/a", eyeson.WebhookRoomUpdate)
+	if err != nil {
+		t.Fatalf("Ensure failed: %v", err)
+	}
+	if kept.Id != webhooks[0].Id || len(fake.Webhooks()) != 1 {
+		t.Errorf("Expected webhook %v to be kept alone, got %v", webhooks[0].Id, fake.Webhooks())
+	}
+	again, err := client.Webhook.Ensure("https://example.com/a", eyeson.WebhookRoomUpdate)
+	if err != nil || again.Id != kept.Id {
+		t.Errorf("Expected Ensure to be idempotent, got %v, %v", again, err)
+	}
+
+	updated, err := client.Webhook.Ensure("https://example.com/a",
+		eyeson.WebhookRoomUpdate, eyeson.WebhookRecordingUpdate)
+	if err != nil {
+		t.Fatalf("Ensure failed: %v", err)
+	}
+	if updated.Id == kept.Id || !updated.TypeSet().Contains(eyeson.WebhookRecordingUpdate) ||
+		len(fake.Webhooks()) != 1 {
+		t.Errorf("Expected webhook to be replaced, got %v", fake.Webhooks())
+	}
+
+	if err = client.Webhook.UnregisterID(updated.Id); err != nil {
+		t.Errorf("UnregisterID failed: %v", err)
+	}
+	if webhooks, err = client.Webhook.List(); err != nil || len(webhooks) != 0 {
+		t.Errorf("Expected no webhooks, got %v, %v", webhooks, err)
+	}
+}
@@ -1,9 +1,10 @@
 module github.com/eyeson-team/eyeson-go
 
-go 1.16
+go 1.21
 
 require (
-	github.com/bgentry/actioncable-go v0.0.0-20170309201021-1f2dbd93dbae
-	github.com/gorilla/websocket v1.5.1 // indirect
-	github.com/jpillora/backoff v1.0.0 // indirect
+	github.com/gorilla/websocket v1.5.1
+	github.com/jpillora/backoff v1.0.0
 )
+
+require golang.org/x/net v0.17.0 // indirect
@@ -0,0 +1,177 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operation describes an API call reported to an Instrumentation.
+type Operation struct {
+	// Name identifies the called method, e.g. rooms.join or user.set_layout.
+	// Requests sent directly via Client.Do are named after their HTTP method,
+	// e.g. http.get.
+	Name string
+	// RoomID is the identifier of the affected room, if known.
+	RoomID string
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the URL path of the request with secrets redacted.
+	Path string
+}
+
+// OperationResult describes the outcome of an API call.
+type OperationResult struct {
+	// StatusCode is the HTTP status code of the response, zero if no response
+	// was received.
+	StatusCode int
+	// Duration is the time spent for the call including all retries.
+	Duration time.Duration
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// ObserverEvent describes an event received by the ObserverService.
+type ObserverEvent struct {
+	// RoomID is the identifier of the observed room.
+	RoomID string
+	// Type is the event type, e.g. chat or podium_update.
+	Type string
+	// ReceivedAt is the time the event has been received.
+	ReceivedAt time.Time
+}
+
+// Instrumentation receives notifications of every API call and every observer
+// event, to be reported as traces or metrics.
+type Instrumentation interface {
+	// StartOperation is called before an API call is sent. The returned
+	// context is used for the request and the returned function is called
+	// with the result once the call has finished.
+	StartOperation(ctx context.Context, op Operation) (context.Context, func(OperationResult))
+	// ObserverEvent is called for every event received by the observer.
+	ObserverEvent(ctx context.Context, event ObserverEvent)
+}
+
+// WithInstrumentation Set an instrumentation to be notified of every API call
+// and every observer event.
+func WithInstrumentation(instrumentation Instrumentation) ClientOption {
+	return func(c *Client) {
+		c.instrumentation = instrumentation
+	}
+}
+
+type operationContextKey struct{}
+
+type operation struct {
+	name   string
+	roomID string
+}
+
+// withOperation names the API call made with the returned context.
+func withOperation(ctx context.Context, name, roomID string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation{name: name, roomID: roomID})
+}
+
+// startOperation notifies the instrumentation of the client about a new API
+// call. It returns the request to be sent and a function to report its
+// result.
+func (c *Client) startOperation(req *http.Request) (*http.Request, func(*http.Response, error)) {
+	if c.instrumentation == nil {
+		return req, func(*http.Response, error) {}
+	}
+	op := Operation{
+		Name:   "http." + strings.ToLower(req.Method),
+		Method: req.Method,
+		Path:   c.redactPath(req.URL.Path),
+	}
+	if named, ok := req.Context().Value(operationContextKey{}).(operation); ok {
+		op.Name = named.name
+		op.RoomID = named.roomID
+	}
+	start := time.Now()
+	ctx, end := c.instrumentation.StartOperation(req.Context(), op)
+	return req.WithContext(ctx), func(resp *http.Response, err error) {
+		result := OperationResult{Duration: time.Since(start), Err: err}
+		var apiErr *APIError
+		if resp != nil {
+			result.StatusCode = resp.StatusCode
+		} else if errors.As(err, &apiErr) {
+			result.StatusCode = apiErr.StatusCode
+		}
+		end(result)
+	}
+}
+
+// observerEvent notifies the instrumentation of the client about a received
+// observer event.
+func (c *Client) observerEvent(ctx context.Context, roomID, eventType string) {
+	if c.instrumentation == nil {
+		return
+	}
+	c.instrumentation.ObserverEvent(ctx, ObserverEvent{
+		RoomID:     roomID,
+		Type:       eventType,
+		ReceivedAt: time.Now(),
+	})
+}
+
+// RecordedOperation is an API call recorded by InMemoryInstrumentation.
+type RecordedOperation struct {
+	Operation
+	OperationResult
+}
+
+// InMemoryInstrumentation records all API calls and observer events in
+// memory. It serves as reference implementation and for assertions in tests.
+type InMemoryInstrumentation struct {
+	mu         sync.Mutex
+	operations []RecordedOperation
+	events     []ObserverEvent
+}
+
+// NewInMemoryInstrumentation creates a new, empty InMemoryInstrumentation.
+func NewInMemoryInstrumentation() *InMemoryInstrumentation {
+	return &InMemoryInstrumentation{}
+}
+
+// StartOperation implements the Instrumentation interface.
+func (i *InMemoryInstrumentation) StartOperation(ctx context.Context,
+	op Operation) (context.Context, func(OperationResult)) {
+	return ctx, func(result OperationResult) {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		i.operations = append(i.operations, RecordedOperation{Operation: op, OperationResult: result})
+	}
+}
+
+// ObserverEvent implements the Instrumentation interface.
+func (i *InMemoryInstrumentation) ObserverEvent(ctx context.Context, event ObserverEvent) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.events = append(i.events, event)
+}
+
+// Operations provides all finished API calls in order of completion.
+func (i *InMemoryInstrumentation) Operations() []RecordedOperation {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]RecordedOperation(nil), i.operations...)
+}
+
+// Events provides all received observer events in order of arrival.
+func (i *InMemoryInstrumentation) Events() []ObserverEvent {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]ObserverEvent(nil), i.events...)
+}
+
+// Reset clears all recorded operations and events.
+func (i *InMemoryInstrumentation) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.operations = nil
+	i.events = nil
+}
@@ -0,0 +1,51 @@
+package eyeson
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestInMemoryInstrumentation(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	instrumentation := NewInMemoryInstrumentation()
+	WithInstrumentation(instrumentation)(client)
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_key":"token","room":{"id":"room-id"}}`)
+	})
+	mux.HandleFunc("/rooms/token/layout", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	})
+
+	user, err := client.Rooms.Join("room-id", "mike", nil)
+	if err != nil {
+		t.Fatalf("RoomsService Join not successfull, got %v", err)
+	}
+	if err = user.SetLayout(Auto, nil); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("Expected ErrBadRequest, got %v", err)
+	}
+
+	ops := instrumentation.Operations()
+	if len(ops) != 2 {
+		t.Fatalf("Expected 2 recorded operations, got %d", len(ops))
+	}
+	if ops[0].Name != "rooms.join" || ops[0].RoomID != "room-id" || ops[0].StatusCode != 200 ||
+		ops[0].Err != nil {
+		t.Errorf("Unexpected join operation %+v", ops[0])
+	}
+	if ops[1].Name != "user.set_layout" || ops[1].RoomID != "room-id" || ops[1].StatusCode != 400 ||
+		ops[1].Err == nil {
+		t.Errorf("Unexpected set layout operation %+v", ops[1])
+	}
+	if ops[1].Path != "/rooms/[REDACTED]/layout" {
+		t.Errorf("Expected redacted path, got %s", ops[1].Path)
+	}
+
+	instrumentation.Reset()
+	if len(instrumentation.Operations()) != 0 {
+		t.Error("Expected Reset to clear recorded operations")
+	}
+}
@@ -0,0 +1,205 @@
+package eyeson
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// SFUMode defines the usage of the selective forwarding unit in a meeting.
+type SFUMode string
+
+const (
+	// SFUModePTP uses the SFU for meetings with up to two participants
+	// (default).
+	SFUModePTP SFUMode = "ptp"
+	// SFUModeDisabled always routes media through the MCU.
+	SFUModeDisabled SFUMode = "disabled"
+)
+
+// JoinCustomFields holds the custom fields of a room used by the eyeson web
+// GUI.
+type JoinCustomFields struct {
+	// Locale sets the language of the GUI, e.g. "en" or "de".
+	Locale string
+	// Logo is an URL to a logo shown in the GUI.
+	Logo string
+	// HideChat hides the chat in the GUI.
+	HideChat *bool
+	// VirtualBackground enables virtual backgrounds.
+	VirtualBackground *bool
+	// VirtualBackgroundAllowGuest allows guests to use virtual backgrounds.
+	VirtualBackgroundAllowGuest *bool
+	// VirtualBackgroundImage is an URL to a default virtual background image.
+	VirtualBackgroundImage string
+}
+
+// JoinOptions provides typed room and user options for JoinWithOptions. All
+// unset fields are omitted, leaving the default of the API in place.
+type JoinOptions struct {
+	// RoomName is the display name of the room.
+	RoomName string
+	// UserID is a custom identifier of the joining user.
+	UserID string
+	// UserAvatar is an URL to the avatar image of the joining user.
+	UserAvatar string
+
+	// Widescreen selects a 16:9 instead of a 4:3 podium.
+	Widescreen *bool
+	// ShowNames shows name overlays of the participants.
+	ShowNames *bool
+	// ShowLabel shows the eyeson label.
+	ShowLabel *bool
+	// ExitURL is the URL participants are sent to when leaving the GUI.
+	ExitURL string
+	// RecordingAvailable allows participants to record the meeting.
+	RecordingAvailable *bool
+	// BroadcastAvailable allows participants to broadcast the meeting.
+	BroadcastAvailable *bool
+	// LayoutAvailable allows participants to change the layout.
+	LayoutAvailable *bool
+	// ReactionAvailable allows participants to send reactions.
+	ReactionAvailable *bool
+	// KickAvailable allows participants to kick other participants.
+	KickAvailable *bool
+	// LockAvailable allows participants to lock the meeting.
+	LockAvailable *bool
+	// GuestTokenAvailable provides a guest token to invite guests.
+	GuestTokenAvailable *bool
+	// SFUMode defines the usage of the selective forwarding unit.
+	SFUMode SFUMode
+	// BackgroundColor is the podium background color in the format #rrggbb.
+	BackgroundColor string
+
+	// Layout is the initial podium layout.
+	Layout Layout
+	// LayoutName is the name of a predefined layout.
+	LayoutName string
+	// LayoutUsers is a list of user ids to be placed on the podium.
+	LayoutUsers []string
+	// LayoutMap contains custom positions of participants.
+	LayoutMap *LayoutMap
+	// VoiceActivation replaces participants actively by voice detection.
+	VoiceActivation *bool
+	// AudioInsert configures the insert shown for audio-only participants.
+	AudioInsert *AudioInsert
+
+	// CustomFields holds options used by the eyeson web GUI.
+	CustomFields *JoinCustomFields
+
+	// Extra holds raw form values for options not covered by this struct,
+	// e.g. "options[custom_fields][foo]". They override typed options.
+	Extra map[string]string
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Validate checks the options for invalid values.
+func (o *JoinOptions) Validate() error {
+	switch o.SFUMode {
+	case "", SFUModePTP, SFUModeDisabled:
+	default:
+		return fmt.Errorf("Invalid sfu mode %q", o.SFUMode)
+	}
+	switch o.Layout {
+	case "", Auto, Custom:
+	default:
+		return fmt.Errorf("Invalid layout %q", o.Layout)
+	}
+	if o.LayoutMap != nil && o.Layout != Custom {
+		return fmt.Errorf("Layout map requires the custom layout")
+	}
+	if o.BackgroundColor != "" && !colorPattern.MatchString(o.BackgroundColor) {
+		return fmt.Errorf("Invalid background color %q, expected #rrggbb", o.BackgroundColor)
+	}
+	urls := map[string]string{"exit url": o.ExitURL, "user avatar": o.UserAvatar}
+	if o.CustomFields != nil {
+		urls["logo"] = o.CustomFields.Logo
+		urls["virtual background image"] = o.CustomFields.VirtualBackgroundImage
+	}
+	for name, raw := range urls {
+		if raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err != nil || !u.IsAbs() {
+			return fmt.Errorf("Invalid %s %q, expected an absolute URL", name, raw)
+		}
+	}
+	if o.AudioInsert != nil {
+		switch o.AudioInsert.Config {
+		case Enabled, Disabled, AudioOnly:
+		default:
+			return fmt.Errorf("Invalid audio insert config %q", o.AudioInsert.Config)
+		}
+	}
+	return nil
+}
+
+// Encode validates the options and encodes them into form values as expected
+// by the API.
+func (o *JoinOptions) Encode() (url.Values, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	data := url.Values{}
+	setString := func(key, value string) {
+		if value != "" {
+			data.Set(key, value)
+		}
+	}
+	setBool := func(key string, value *bool) {
+		if value != nil {
+			data.Set(key, strconv.FormatBool(*value))
+		}
+	}
+
+	setString("name", o.RoomName)
+	setString("user[id]", o.UserID)
+	setString("user[avatar]", o.UserAvatar)
+
+	setBool("options[widescreen]", o.Widescreen)
+	setBool("options[show_names]", o.ShowNames)
+	setBool("options[show_label]", o.ShowLabel)
+	setString("options[exit_url]", o.ExitURL)
+	setBool("options[recording_available]", o.RecordingAvailable)
+	setBool("options[broadcast_available]", o.BroadcastAvailable)
+	setBool("options[layout_available]", o.LayoutAvailable)
+	setBool("options[reaction_available]", o.ReactionAvailable)
+	setBool("options[kick_available]", o.KickAvailable)
+	setBool("options[lock_available]", o.LockAvailable)
+	setBool("options[guest_token_available]", o.GuestTokenAvailable)
+	setString("options[sfu_mode]", string(o.SFUMode))
+	setString("options[background_color]", o.BackgroundColor)
+
+	setString("options[layout]", string(o.Layout))
+	setString("options[layout_name]", o.LayoutName)
+	for _, userID := range o.LayoutUsers {
+		data.Add("options[layout_users][]", userID)
+	}
+	if o.LayoutMap != nil {
+		data.Set("options[layout_map]", o.LayoutMap.toString())
+	}
+	setBool("options[voice_activation]", o.VoiceActivation)
+	if o.AudioInsert != nil {
+		data.Set("options[audio_insert]", string(o.AudioInsert.Config))
+		if o.AudioInsert.Position != nil {
+			data.Set("options[audio_insert_position][x]", strconv.Itoa(o.AudioInsert.Position.X))
+			data.Set("options[audio_insert_position][y]", strconv.Itoa(o.AudioInsert.Position.Y))
+		}
+	}
+
+	if f := o.CustomFields; f != nil {
+		setString("options[custom_fields][locale]", f.Locale)
+		setString("options[custom_fields][logo]", f.Logo)
+		setBool("options[custom_fields][hide_chat]", f.HideChat)
+		setBool("options[custom_fields][virtual_background]", f.VirtualBackground)
+		setBool("options[custom_fields][virtual_background_allow_guest]", f.VirtualBackgroundAllowGuest)
+		setString("options[custom_fields][virtual_background_image]", f.VirtualBackgroundImage)
+	}
+
+	for k, v := range o.Extra {
+		data.Set(k, v)
+	}
+	return data, nil
+}
@@ -0,0 +1,87 @@
+package eyeson
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRoomsService_JoinWithOptions(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	yes, no := true, false
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testFormValuesArray(t, r, url.Values{
+			"id":                                   {"standup"},
+			"name":                                 {"Standup"},
+			"user[name]":                           {"mike"},
+			"user[id]":                             {"mike-id"},
+			"options[widescreen]":                  {"true"},
+			"options[recording_available]":         {"false"},
+			"options[exit_url]":                    {"https://example.com/bye"},
+			"options[sfu_mode]":                    {"disabled"},
+			"options[layout]":                      {"custom"},
+			"options[layout_users][]":              {"a", "b"},
+			"options[layout_map]":                  {`[[0, 0, 640, 360, "auto"]]`},
+			"options[custom_fields][locale]":       {"de"},
+			"options[custom_fields][hide_chat]":    {"true"},
+			"options[custom_fields][experimental]": {"on"},
+		})
+		fmt.Fprint(w, `{"access_key":"token"}`)
+	})
+
+	_, err := client.Rooms.JoinWithOptions("standup", "mike", &JoinOptions{
+		RoomName:           "Standup",
+		UserID:             "mike-id",
+		Widescreen:         &yes,
+		RecordingAvailable: &no,
+		ExitURL:            "https://example.com/bye",
+		SFUMode:            SFUModeDisabled,
+		Layout:             Custom,
+		LayoutUsers:        []string{"a", "b"},
+		LayoutMap:          &LayoutMap{Positions: []LayoutPos{{0, 0, 640, 360, Autofit}}},
+		CustomFields:       &JoinCustomFields{Locale: "de", HideChat: &yes},
+		Extra:              map[string]string{"options[custom_fields][experimental]": "on"},
+	})
+	if err != nil {
+		t.Errorf("RoomsService JoinWithOptions not successfull, got %v", err)
+	}
+}
+
+func TestJoinOptions_Validate(t *testing.T) {
+	invalid := []JoinOptions{
+		{SFUMode: "always"},
+		{Layout: "grid"},
+		{LayoutMap: &LayoutMap{}},
+		{BackgroundColor: "red"},
+		{ExitURL: "/relative"},
+		{CustomFields: &JoinCustomFields{Logo: "logo.png"}},
+		{AudioInsert: &AudioInsert{Config: "sometimes"}},
+	}
+	for _, o := range invalid {
+		if err := o.Validate(); err == nil {
+			t.Errorf("Expected options %+v to be invalid", o)
+		}
+	}
+	valid := JoinOptions{SFUMode: SFUModePTP, Layout: Auto, BackgroundColor: "#121212",
+		AudioInsert: &AudioInsert{Config: AudioOnly}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected options to be valid, got %v", err)
+	}
+}
+
+func TestRoomsService_JoinWithOptions_invalid(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Invalid options should not be sent")
+	})
+
+	if _, err := client.Rooms.JoinWithOptions("", "mike", &JoinOptions{SFUMode: "always"}); err == nil {
+		t.Error("Expected JoinWithOptions to fail on invalid options")
+	}
+}
@@ -0,0 +1,205 @@
+package eyeson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets in log output.
+const redacted = "[REDACTED]"
+
+// maxLogBodySize limits the amount of a response body written to the log.
+const maxLogBodySize = 16 * 1024
+
+// Logger is the structured logger used by the client. It is implemented by
+// *slog.Logger.
+type Logger interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+	Log(ctx context.Context, level slog.Level, msg string, args ...interface{})
+}
+
+// WithLogger Set a logger to log every request sent to the API. Requests are
+// logged with method, path, status and latency on info level, failed ones on
+// warn level. On debug level, request parameters and response bodies are
+// logged as well. Secrets like the API key, access keys, guest tokens, SIP
+// passwords and TURN credentials are redacted.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// redactedKeys lists parameter and JSON attribute names that hold secrets.
+var redactedKeys = map[string]bool{
+	"access_key":        true,
+	"guest_token":       true,
+	"token":             true,
+	"password":          true,
+	"auth_token":        true,
+	"authorizationUser": true,
+	"username":          true,
+	"credential":        true,
+	"stream_url":        true,
+	"gui":               true,
+	"guest_join":        true,
+	"websocket":         true,
+}
+
+// redactPath hides access keys and guest tokens within an URL path. Room
+// identifiers following /rooms/ are only hidden for user clients, where they
+// represent the access key.
+func (c *Client) redactPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		switch segments[i-1] {
+		case "guests":
+			segments[i] = redacted
+		case "rooms":
+			if c.apiKey == "" && segments[i] != "" {
+				segments[i] = redacted
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// redactError provides the error message with secrets hidden from the URL,
+// which errors of the HTTP client include.
+func (c *Client) redactError(err error) string {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err.Error()
+	}
+	redactedURL := urlErr.URL
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+		u.Path = c.redactPath(u.Path)
+		u.RawPath = ""
+		u.RawQuery = ""
+		redactedURL = u.String()
+	} else {
+		redactedURL = redacted
+	}
+	return fmt.Sprintf("%s %q: %s", urlErr.Op, redactedURL, urlErr.Err)
+}
+
+// redactValues provides the values as string with all secrets hidden.
+func redactValues(values url.Values) string {
+	clean := url.Values{}
+	for k, v := range values {
+		if redactedKeys[strings.TrimSuffix(k, "[]")] {
+			clean[k] = []string{redacted}
+			continue
+		}
+		clean[k] = v
+	}
+	return clean.Encode()
+}
+
+// redactJSON hides all secrets within a decoded JSON document.
+func redactJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if redactedKeys[k] {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactJSON(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = redactJSON(val)
+		}
+	}
+	return v
+}
+
+// redactBody provides a response body for logging with all secrets hidden.
+func (c *Client) redactBody(body []byte) string {
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		if encoded, err := json.Marshal(redactJSON(decoded)); err == nil {
+			body = encoded
+		}
+	}
+	if len(body) > maxLogBodySize {
+		body = body[:maxLogBodySize]
+	}
+	out := string(body)
+	if c.apiKey != "" {
+		out = strings.ReplaceAll(out, c.apiKey, redacted)
+	}
+	return out
+}
+
+// requestParams provides the redacted query or form parameters of a request.
+func requestParams(req *http.Request) string {
+	if req.URL.RawQuery != "" {
+		return redactValues(req.URL.Query())
+	}
+	if req.GetBody == nil ||
+		!strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return ""
+	}
+	return redactValues(values)
+}
+
+// logRequest logs a single attempt of a request. The response body is read
+// and replaced if it is logged.
+func (c *Client) logRequest(req *http.Request, resp *http.Response, err error, start time.Time) {
+	ctx := req.Context()
+	args := []interface{}{
+		slog.String("method", req.Method),
+		slog.String("path", c.redactPath(req.URL.Path)),
+		slog.Duration("latency", time.Since(start)),
+	}
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		args = append(args, slog.String("error", c.redactError(err)))
+	} else {
+		args = append(args, slog.Int("status", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+	if c.logger.Enabled(ctx, slog.LevelDebug) {
+		if params := requestParams(req); params != "" {
+			args = append(args, slog.String("params", params))
+		}
+		if resp != nil && resp.Body != nil {
+			body, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if readErr == nil && len(body) > 0 {
+				args = append(args, slog.String("body", c.redactBody(body)))
+			}
+		}
+	}
+	c.logger.Log(ctx, level, "eyeson api request", args...)
+}
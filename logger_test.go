@@ -0,0 +1,92 @@
+package eyeson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_logger(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	WithLogger(logger)(client)
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_key":"secret-access-key","room":{"guest_token":"secret-guest",`+
+			`"sip":{"password":"sip-secret"}},"signaling":{"options":{"turn_servers":`+
+			`[{"username":"turn-user","password":"turn-secret"}]}},"user":{"name":"mike"}}`)
+	})
+	mux.HandleFunc("/rooms/secret-access-key/broadcasts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+	})
+
+	user, err := client.Rooms.Join("standup", "mike", nil)
+	if err != nil {
+		t.Fatalf("RoomsService Join not successfull, got %v", err)
+	}
+	if user.Data.AccessKey != "secret-access-key" {
+		t.Errorf("Expected logging to keep the response body, got access key %q", user.Data.AccessKey)
+	}
+	if err = user.StartBroadcast("rtmp://stream.example.com/live/stream-key"); err != nil {
+		t.Fatalf("UserService StartBroadcast not successfull, got %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"method=POST", "path=/rooms", "status=201", "latency=",
+		"user%5Bname%5D=mike", "/rooms/[REDACTED]/broadcasts", `\"name\":\"mike\"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log to contain %q, got %s", want, out)
+		}
+	}
+	for _, secret := range []string{testAPIKey, "secret-access-key", "secret-guest", "sip-secret",
+		"turn-user", "turn-secret", "stream-key"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected log not to contain %q, got %s", secret, out)
+		}
+	}
+}
+
+func TestClient_loggerTransportError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	failing := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+	client, err := NewClient("", WithHTTPClient(failing), WithLogger(logger),
+		WithCustomEndpoint("https://api.example.com"))
+	if err != nil {
+		t.Fatalf("Failed to init client: %s", err)
+	}
+
+	req, _ := client.NewRequest(http.MethodGet, "/rooms/secret-access-key", nil)
+	if _, err = client.Do(req, nil); err == nil {
+		t.Fatal("Expected transport error")
+	}
+	out := buf.String()
+	if strings.Contains(out, "secret-access-key") {
+		t.Errorf("Expected log not to contain the access key, got %s", out)
+	}
+	if !strings.Contains(out, "/rooms/[REDACTED]") || !strings.Contains(out, "connection refused") {
+		t.Errorf("Expected log to contain redacted URL and error, got %s", out)
+	}
+}
+
+func TestClient_redactPath(t *testing.T) {
+	apiClient := &Client{apiKey: "key"}
+	if got := apiClient.redactPath("/rooms/room-id/users"); got != "/rooms/room-id/users" {
+		t.Errorf("Expected room id to be kept for API clients, got %s", got)
+	}
+	if got := apiClient.redactPath("/guests/guest-token"); got != "/guests/[REDACTED]" {
+		t.Errorf("Expected guest token to be redacted, got %s", got)
+	}
+	userClient := apiClient.UserClient()
+	if got := userClient.redactPath("/rooms/access-key/layout"); got != "/rooms/[REDACTED]/layout" {
+		t.Errorf("Expected access key to be redacted, got %s", got)
+	}
+}
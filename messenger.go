@@ -0,0 +1,230 @@
+package eyeson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Kinds of message envelopes.
+const (
+	// MessageKindRequest a request expecting a response.
+	MessageKindRequest = "request"
+	// MessageKindResponse a response to a request with the same id.
+	MessageKindResponse = "response"
+	// MessageKindNotification a message without response.
+	MessageKindNotification = "notification"
+)
+
+// MessageEnvelope is the JSON content of custom messages sent by a Messenger.
+type MessageEnvelope struct {
+	Kind string `json:"kind"`
+	// Type is the method of requests and notifications.
+	Type string `json:"type"`
+	// ID correlates requests and responses.
+	ID      string          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Error is the error message of a failed request.
+	Error string `json:"error,omitempty"`
+}
+
+// IncomingMessage is a request or notification received by a Messenger.
+type IncomingMessage struct {
+	MessageEnvelope
+	// From is the user id of the sender.
+	From string
+	// Event is the custom message carrying the envelope.
+	Event *CustomMessage
+}
+
+// Decode decodes the payload into v.
+func (m *IncomingMessage) Decode(v interface{}) error {
+	if len(m.Payload) == 0 {
+		return nil
+	}
+	return json.Unmarshal(m.Payload, v)
+}
+
+// MessageHandler handles an incoming message. The result, or the error, is
+// sent back as response to requests and ignored for notifications.
+type MessageHandler func(ctx context.Context, msg *IncomingMessage) (result interface{}, err error)
+
+// RemoteError is returned by Messenger.Call if the remote handler failed.
+type RemoteError struct {
+	Method  string
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("Call of %s failed: %s", e.Method, e.Message)
+}
+
+// Messenger implements typed messages and request/response calls on top of
+// custom messages, to coordinate with web clients and other backends through
+// a meeting. Every custom message contains a JSON encoded MessageEnvelope.
+type Messenger struct {
+	user *UserService
+
+	mu       sync.Mutex
+	handlers map[string]MessageHandler
+	pending  map[string]chan *MessageEnvelope
+	wg       sync.WaitGroup
+}
+
+// NewMessenger creates a messenger sending as the given user.
+func NewMessenger(user *UserService) *Messenger {
+	return &Messenger{
+		user:     user,
+		handlers: map[string]MessageHandler{},
+		pending:  map[string]chan *MessageEnvelope{},
+	}
+}
+
+// Handle registers a handler for requests and notifications of the given
+// type. Handlers run in their own goroutine. Messages of other types are
+// ignored.
+func (m *Messenger) Handle(msgType string, handler MessageHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[msgType] = handler
+}
+
+// Send sends a notification with the payload encoded as JSON.
+func (m *Messenger) Send(ctx context.Context, msgType string, payload interface{}) error {
+	return m.send(ctx, MessageEnvelope{Kind: MessageKindNotification, Type: msgType}, payload)
+}
+
+// Call sends a request and waits for its response until the context is done.
+// The result of the response is decoded into result, if not nil. Messengers
+// without handler for the method ignore the request, so a call nobody handles
+// ends with the context.
+func (m *Messenger) Call(ctx context.Context, method string, params, result interface{}) error {
+	id, err := newMessageID()
+	if err != nil {
+		return err
+	}
+	responses := make(chan *MessageEnvelope, 1)
+	m.mu.Lock()
+	m.pending[id] = responses
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.pending, id)
+		m.mu.Unlock()
+	}()
+
+	err = m.send(ctx, MessageEnvelope{Kind: MessageKindRequest, Type: method, ID: id}, params)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case response := <-responses:
+		if response.Error != "" {
+			return &RemoteError{Method: method, Message: response.Error}
+		}
+		if result == nil || len(response.Payload) == 0 {
+			return nil
+		}
+		return json.Unmarshal(response.Payload, result)
+	}
+}
+
+// Run handles all events of the channel until the context is done or the
+// channel is closed. It waits for running handlers before it returns.
+func (m *Messenger) Run(ctx context.Context, events <-chan EventInterface) error {
+	defer m.wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			m.HandleEvent(ctx, event)
+		}
+	}
+}
+
+// HandleEvent processes a single observer event. Custom messages not sent by
+// a Messenger and messages sent by its own user are ignored. HandleEvent can
+// be registered with EventRouter.OnAny.
+func (m *Messenger) HandleEvent(ctx context.Context, event EventInterface) {
+	custom, ok := event.(*CustomMessage)
+	if !ok || (m.user.Data != nil && custom.UserID == m.user.Data.User.ID) {
+		return
+	}
+	var envelope MessageEnvelope
+	if err := json.Unmarshal([]byte(custom.Content), &envelope); err != nil || envelope.Type == "" {
+		return
+	}
+
+	switch envelope.Kind {
+	case MessageKindResponse:
+		m.mu.Lock()
+		responses, ok := m.pending[envelope.ID]
+		m.mu.Unlock()
+		if ok {
+			select {
+			case responses <- &envelope:
+			default:
+			}
+		}
+	case MessageKindRequest, MessageKindNotification:
+		m.mu.Lock()
+		handler, ok := m.handlers[envelope.Type]
+		m.mu.Unlock()
+		if !ok {
+			// other participants may handle it
+			return
+		}
+		msg := &IncomingMessage{MessageEnvelope: envelope, From: custom.UserID, Event: custom}
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.dispatch(ctx, handler, msg)
+		}()
+	}
+}
+
+// dispatch runs the handler of a message and responds to requests.
+func (m *Messenger) dispatch(ctx context.Context, handler MessageHandler, msg *IncomingMessage) {
+	result, err := handler(ctx, msg)
+	if msg.Kind != MessageKindRequest {
+		return
+	}
+	response := MessageEnvelope{Kind: MessageKindResponse, Type: msg.Type, ID: msg.ID}
+	if err != nil {
+		response.Error = err.Error()
+		result = nil
+	}
+	m.send(ctx, response, result)
+}
+
+func (m *Messenger) send(ctx context.Context, envelope MessageEnvelope, payload interface{}) error {
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		envelope.Payload = raw
+	}
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return m.user.SendCustomMessageContext(ctx, string(content))
+}
+
+func newMessageID() (string, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
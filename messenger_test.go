@@ -0,0 +1,137 @@
+package eyeson_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"github.com/eyeson-team/eyeson-go/eyesontest"
+)
+
+func TestMessenger_call(t *testing.T) {
+	fake := eyesontest.NewServer("rpc-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("rpc-key", eyeson.WithCustomEndpoint(fake.URL))
+	alice, err := client.Rooms.Join("rpc", "alice", nil)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	bob, err := client.Rooms.Join("rpc", "bob", nil)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	caller := eyeson.NewMessenger(alice)
+	callee := eyeson.NewMessenger(bob)
+	notified := make(chan string, 1)
+	callee.Handle("sum", func(ctx context.Context, msg *eyeson.IncomingMessage) (interface{}, error) {
+		var params []int
+		if err := msg.Decode(&params); err != nil {
+			return nil, err
+		}
+		if msg.From != alice.Data.User.ID {
+			t.Errorf("Expected request from alice, got %q", msg.From)
+		}
+		sum := 0
+		for _, v := range params {
+			sum += v
+		}
+		return sum, nil
+	})
+	callee.Handle("fail", func(ctx context.Context, msg *eyeson.IncomingMessage) (interface{}, error) {
+		return nil, errors.New("broken")
+	})
+	callee.Handle("hello", func(ctx context.Context, msg *eyeson.IncomingMessage) (interface{}, error) {
+		var name string
+		msg.Decode(&name)
+		notified <- name
+		return "ignored", nil
+	})
+	callee.Handle("slow", func(ctx context.Context, msg *eyeson.IncomingMessage) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	for _, m := range []*eyeson.Messenger{caller, callee} {
+		events, err := client.Observer.Connect(ctx, "rpc")
+		if err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		go m.Run(ctx, events)
+	}
+	for fake.Observers("rpc") != 2 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var sum int
+	if err = caller.Call(ctx, "sum", []int{1, 2, 3}, &sum); err != nil || sum != 6 {
+		t.Errorf("Expected sum 6, got %d (%v)", sum, err)
+	}
+	var remoteErr *eyeson.RemoteError
+	if err = caller.Call(ctx, "fail", nil, nil); !errors.As(err, &remoteErr) || remoteErr.Message != "broken" {
+		t.Errorf("Expected remote error, got %v", err)
+	}
+	if err = caller.Send(ctx, "hello", "alice"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	select {
+	case name := <-notified:
+		if name != "alice" {
+			t.Errorf("Expected notification payload alice, got %q", name)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timeout waiting for notification")
+	}
+
+	short, stop := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer stop()
+	if err = caller.Call(short, "slow", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected timeout of slow call, got %v", err)
+	}
+}
+
+func TestMessenger_unhandledMethods(t *testing.T) {
+	fake := eyesontest.NewServer("rpc-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("rpc-key", eyeson.WithCustomEndpoint(fake.URL))
+	messengers := []*eyeson.Messenger{}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		user, err := client.Rooms.Join("rpc", name, nil)
+		if err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		messengers = append(messengers, eyeson.NewMessenger(user))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	caller, carol := messengers[0], messengers[2]
+	carol.Handle("ping", func(ctx context.Context, msg *eyeson.IncomingMessage) (interface{}, error) {
+		return "pong", nil
+	})
+	for _, m := range messengers {
+		events, err := client.Observer.Connect(ctx, "rpc")
+		if err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		go m.Run(ctx, events)
+	}
+	for fake.Observers("rpc") != 3 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for i := 0; i < 10; i++ {
+		var reply string
+		if err := caller.Call(ctx, "ping", nil, &reply); err != nil || reply != "pong" {
+			t.Fatalf("Expected pong from carol, got %q (%v)", reply, err)
+		}
+	}
+	short, stop := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer stop()
+	if err := caller.Call(short, "missing", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected unhandled call to time out, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package eyeson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// roomChannelIdentifier is the ActionCable identifier of the room channel.
+const roomChannelIdentifier = `{"channel":"RoomChannel"}`
+
+// errSubscriptionRejected is returned if the server rejects the subscription
+// to the room channel. Reconnecting does not help in this case.
+var errSubscriptionRejected = errors.New("Subscription to room channel rejected")
+
+// errDisconnected is returned if the server closes the connection and asks
+// the client not to reconnect, e.g. after the room has been shut down.
+var errDisconnected = errors.New("Disconnected by server")
+
+// cableMessage is a message sent by the ActionCable server.
+type cableMessage struct {
+	Type       string          `json:"type"`
+	Identifier string          `json:"identifier"`
+	Message    json.RawMessage `json:"message"`
+	Reconnect  *bool           `json:"reconnect"`
+}
+
+// cableCommand is a command sent to the ActionCable server.
+type cableCommand struct {
+	Command    string `json:"command"`
+	Identifier string `json:"identifier"`
+}
+
+// dialCable opens the ActionCable websocket and subscribes to the room
+// channel. The connection uses the TLS and proxy settings of the client's
+// transport, if available.
+func (os *ObserverService) dialCable(ctx context.Context, wsURL string,
+	timeout time.Duration) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: timeout,
+	}
+	// middlewares may wrap the transport, use the one below them
+	tr := os.client.transport
+	if tr == nil {
+		tr = http.DefaultTransport
+	}
+	if tr, ok := tr.(*http.Transport); ok {
+		dialer.Proxy = tr.Proxy
+		dialer.TLSClientConfig = tr.TLSClientConfig
+	}
+	header := http.Header{}
+	header.Set("Authorization", os.client.apiKey)
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("Failed to connect to observer: %s (status %d)", err, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("Failed to connect to observer: %s", err)
+	}
+	if err = conn.SetReadDeadline(time.Now().Add(timeout)); err == nil {
+		var msg cableMessage
+		if err = conn.ReadJSON(&msg); err == nil && msg.Type != "welcome" {
+			err = fmt.Errorf("unexpected message %q", msg.Type)
+		}
+	}
+	if err == nil {
+		err = conn.WriteJSON(cableCommand{Command: "subscribe", Identifier: roomChannelIdentifier})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to subscribe: %s", err)
+	}
+	return conn, nil
+}
+
+// receiveCable reads messages from the connection until it fails or the
+// context is done. Every message, including pings, has to arrive within
+// timeout. onConfirm is called once the subscription is confirmed and
+// onMessage for every message of the room channel.
+func receiveCable(ctx context.Context, conn *websocket.Conn, timeout time.Duration,
+	onConfirm func() bool, onMessage func(json.RawMessage) bool) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+		var msg cableMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		switch msg.Type {
+		case "ping", "welcome":
+		case "confirm_subscription":
+			if !onConfirm() {
+				return ctx.Err()
+			}
+		case "reject_subscription":
+			return errSubscriptionRejected
+		case "disconnect":
+			if msg.Reconnect != nil && !*msg.Reconnect {
+				return errDisconnected
+			}
+			return fmt.Errorf("Server closed the connection")
+		case "":
+			if msg.Identifier != roomChannelIdentifier || len(msg.Message) == 0 {
+				continue
+			}
+			if !onMessage(msg.Message) {
+				return ctx.Err()
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package eyeson
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RoomEvent is an observer event of a room watched by an ObserverPool.
+type RoomEvent struct {
+	RoomID string
+	Event  EventInterface
+}
+
+// ObserverPoolOptions configures an ObserverPool.
+type ObserverPoolOptions struct {
+	// Discover attaches all running meetings by polling
+	// RoomsService.GetCurrentMeetings, and detaches them once they are no
+	// longer listed.
+	Discover bool
+	// PollInterval is the interval of the discovery polling. Defaults to 30s.
+	PollInterval time.Duration
+	// Observer configures the connection of every room.
+	Observer *ObserverOptions
+	// OnError is called if a room cannot be attached or the discovery polling
+	// fails. The room id is empty for polling errors.
+	OnError func(roomID string, err error)
+}
+
+type pooledRoom struct {
+	cancel     context.CancelFunc
+	discovered bool
+}
+
+// ObserverPool maintains one observer connection per room and merges their
+// events into a single stream. Rooms are detached when they shut down or
+// their connection is lost.
+type ObserverPool struct {
+	client  *Client
+	options ObserverPoolOptions
+	ctx     context.Context
+	events  chan RoomEvent
+
+	mu     sync.Mutex
+	rooms  map[string]*pooledRoom
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewObserverPool creates a pool attached to the given rooms. The pool runs
+// until the context is done; its event channel is closed afterwards.
+func NewObserverPool(ctx context.Context, client *Client, options *ObserverPoolOptions,
+	roomIDs ...string) *ObserverPool {
+	p := &ObserverPool{
+		client: client,
+		ctx:    ctx,
+		events: make(chan RoomEvent, 1),
+		rooms:  map[string]*pooledRoom{},
+	}
+	if options != nil {
+		p.options = *options
+	}
+	if p.options.PollInterval <= 0 {
+		p.options.PollInterval = 30 * time.Second
+	}
+
+	for _, roomID := range roomIDs {
+		if err := p.Add(roomID); err != nil {
+			p.reportError(roomID, err)
+		}
+	}
+	if p.options.Discover {
+		p.wg.Add(1)
+		go p.discover()
+	}
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+		p.wg.Wait()
+		close(p.events)
+	}()
+	return p
+}
+
+// Events provides the merged events of all attached rooms.
+func (p *ObserverPool) Events() <-chan RoomEvent {
+	return p.events
+}
+
+// Rooms provides the ids of all attached rooms in sorted order.
+func (p *ObserverPool) Rooms() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make([]string, 0, len(p.rooms))
+	for id := range p.rooms {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Add attaches a room. Adding an attached room has no effect.
+func (p *ObserverPool) Add(roomID string) error {
+	return p.add(roomID, false)
+}
+
+// Remove detaches a room.
+func (p *ObserverPool) Remove(roomID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if room, ok := p.rooms[roomID]; ok {
+		room.cancel()
+		delete(p.rooms, roomID)
+	}
+}
+
+func (p *ObserverPool) add(roomID string, discovered bool) error {
+	if p.attached(roomID) {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(p.ctx)
+	events, err := p.client.Observer.ConnectWithOptions(ctx, roomID, p.options.Observer)
+	if err != nil {
+		cancel()
+		if p.ctx.Err() != nil {
+			return fmt.Errorf("Observer pool closed")
+		}
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		cancel()
+		return fmt.Errorf("Observer pool closed")
+	}
+	if _, ok := p.rooms[roomID]; ok {
+		// attached concurrently
+		cancel()
+		return nil
+	}
+	room := &pooledRoom{cancel: cancel, discovered: discovered}
+	p.rooms[roomID] = room
+	p.wg.Add(1)
+	go p.forward(ctx, roomID, room, events)
+	return nil
+}
+
+// attached reports whether the room is attached.
+func (p *ObserverPool) attached(roomID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.rooms[roomID]
+	return ok
+}
+
+// forward passes the events of a room to the pool until the room shuts down
+// or its connection ends.
+func (p *ObserverPool) forward(ctx context.Context, roomID string, room *pooledRoom,
+	events <-chan EventInterface) {
+	defer p.wg.Done()
+	defer func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		room.cancel()
+		if p.rooms[roomID] == room {
+			delete(p.rooms, roomID)
+		}
+	}()
+
+	for event := range events {
+		select {
+		case p.events <- RoomEvent{RoomID: roomID, Event: event}:
+		case <-ctx.Done():
+			return
+		}
+		if ev, ok := event.(*RoomUpdate); ok && ev.Content.Shutdown {
+			return
+		}
+	}
+}
+
+// discover polls the running meetings and attaches or detaches rooms.
+func (p *ObserverPool) discover() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		p.poll()
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ObserverPool) poll() {
+	meetings, err := p.client.Rooms.GetCurrentMeetingsContext(p.ctx)
+	if err != nil {
+		if p.ctx.Err() == nil {
+			p.reportError("", err)
+		}
+		return
+	}
+	running := map[string]bool{}
+	for _, meeting := range *meetings {
+		if meeting.Shutdown {
+			continue
+		}
+		running[meeting.ID] = true
+		if err := p.add(meeting.ID, true); err != nil && p.ctx.Err() == nil {
+			p.reportError(meeting.ID, err)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, room := range p.rooms {
+		if room.discovered && !running[id] {
+			room.cancel()
+			delete(p.rooms, id)
+		}
+	}
+}
+
+func (p *ObserverPool) reportError(roomID string, err error) {
+	if p.options.OnError != nil {
+		p.options.OnError(roomID, err)
+	}
+}
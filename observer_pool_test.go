@@ -0,0 +1,104 @@
+package eyeson_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"github.com/eyeson-team/eyeson-go/eyesontest"
+)
+
+func nextRoomEvent(t *testing.T, ctx context.Context, pool *eyeson.ObserverPool,
+	match func(eyeson.RoomEvent) bool) eyeson.RoomEvent {
+	t.Helper()
+	for {
+		select {
+		case ev, ok := <-pool.Events():
+			if !ok {
+				t.Fatal("Pool events closed unexpectedly")
+			}
+			if match(ev) {
+				return ev
+			}
+		case <-ctx.Done():
+			t.Fatal("Timeout waiting for pool event")
+		}
+	}
+}
+
+func TestObserverPool_discover(t *testing.T) {
+	fake := eyesontest.NewServer("pool-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("pool-key", eyeson.WithCustomEndpoint(fake.URL))
+	alpha, err := client.Rooms.Join("alpha", "mike", nil)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pool := eyeson.NewObserverPool(ctx, client, &eyeson.ObserverPoolOptions{
+		Discover:     true,
+		PollInterval: 20 * time.Millisecond,
+		OnError:      func(roomID string, err error) { t.Errorf("Unexpected error for %q: %v", roomID, err) },
+	})
+	isRoomUpdate := func(roomID string) func(eyeson.RoomEvent) bool {
+		return func(ev eyeson.RoomEvent) bool {
+			_, ok := ev.Event.(*eyeson.RoomUpdate)
+			return ok && ev.RoomID == roomID
+		}
+	}
+	nextRoomEvent(t, ctx, pool, isRoomUpdate("alpha"))
+
+	if _, err = client.Rooms.Join("beta", "mike", nil); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	nextRoomEvent(t, ctx, pool, isRoomUpdate("beta"))
+	if rooms := pool.Rooms(); len(rooms) != 2 || rooms[0] != "alpha" || rooms[1] != "beta" {
+		t.Errorf("Expected rooms alpha and beta, got %v", rooms)
+	}
+
+	if err = alpha.StopMeeting(); err != nil {
+		t.Fatalf("StopMeeting failed: %v", err)
+	}
+	ev := nextRoomEvent(t, ctx, pool, isRoomUpdate("alpha"))
+	if !ev.Event.(*eyeson.RoomUpdate).Content.Shutdown {
+		t.Errorf("Expected shutdown room update, got %#v", ev.Event)
+	}
+	for len(pool.Rooms()) != 1 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	for range pool.Events() {
+	}
+	if err := pool.Add("beta"); err == nil {
+		t.Error("Expected Add on closed pool to fail")
+	}
+}
+
+func TestObserverPool_explicitRooms(t *testing.T) {
+	fake := eyesontest.NewServer("pool-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("pool-key", eyeson.WithCustomEndpoint(fake.URL))
+	if _, err := client.Rooms.Join("gamma", "mike", nil); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	var failed []string
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pool := eyeson.NewObserverPool(ctx, client, &eyeson.ObserverPoolOptions{
+		OnError: func(roomID string, err error) { failed = append(failed, roomID) },
+	}, "gamma", "missing")
+	if len(failed) != 1 || failed[0] != "missing" {
+		t.Errorf("Expected missing room to fail, got %v", failed)
+	}
+	nextRoomEvent(t, ctx, pool, func(ev eyeson.RoomEvent) bool { return ev.RoomID == "gamma" })
+
+	pool.Remove("gamma")
+	if rooms := pool.Rooms(); len(rooms) != 0 {
+		t.Errorf("Expected no rooms after remove, got %v", rooms)
+	}
+}
@@ -3,13 +3,15 @@ package eyeson
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
-	ac "github.com/bgentry/actioncable-go"
+	"github.com/gorilla/websocket"
+	"github.com/jpillora/backoff"
 )
 
 // ObserverService Service to listen and control a room.
@@ -46,6 +48,8 @@ type EventRoom struct {
 	Options      Options       `json:"options"`
 	Participants []Participant `json:"participants"`
 	Broadcasts   []Broadcast   `json:"broadcasts"`
+	// SIP is only provided by webhooks.
+	SIP *SIP `json:"sip,omitempty"`
 }
 
 // RoomUpdate event is sent if any of the room properties is changed.
@@ -190,6 +194,44 @@ type PlaybackUpdate struct {
 	Playing []Playback `json:"playing"`
 }
 
+// RawEvent is an event of a type without registered struct, e.g. an event
+// introduced by the API after this client. It carries the undecoded message.
+type RawEvent struct {
+	EventBase
+	Raw json.RawMessage `json:"-"`
+}
+
+// MarshalJSON provides the original message of the event.
+func (ev *RawEvent) MarshalJSON() ([]byte, error) {
+	if len(ev.Raw) == 0 {
+		return json.Marshal(ev.EventBase)
+	}
+	return ev.Raw, nil
+}
+
+// EventDecodeError describes an observer message that could not be decoded.
+type EventDecodeError struct {
+	// Type is the event type, empty if the message is not valid JSON.
+	Type string
+	// Raw is the received message.
+	Raw json.RawMessage
+	// Err is the error of the JSON decoder.
+	Err error
+}
+
+func (e *EventDecodeError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("Failed to decode observer message: %s", e.Err)
+	}
+	return fmt.Sprintf("Failed to decode observer event %s: %s", e.Type, e.Err)
+}
+
+func (e *EventDecodeError) Unwrap() error {
+	return e.Err
+}
+
+var eventTypesMu sync.RWMutex
+
 var eventTypes = map[string]func() EventInterface{
 	"room_update":        func() EventInterface { return &RoomUpdate{} },
 	"participant_update": func() EventInterface { return &ParticipantUpdate{} },
@@ -203,14 +245,112 @@ var eventTypes = map[string]func() EventInterface{
 	"custom":             func() EventInterface { return &CustomMessage{} },
 }
 
+// RegisterEventType registers a factory for the struct of an event type, to
+// decode events not supported by this package or to replace the provided
+// structs. The factory has to return a new pointer on every call.
+func RegisterEventType(name string, factory func() EventInterface) {
+	eventTypesMu.Lock()
+	defer eventTypesMu.Unlock()
+	eventTypes[name] = factory
+}
+
+// Connection states reported by ConnectionState events.
+const (
+	// ConnectionConnected the observer is subscribed to the room.
+	ConnectionConnected = "connected"
+	// ConnectionReconnecting the connection failed, a reconnect is pending.
+	ConnectionReconnecting = "reconnecting"
+	// ConnectionLost the connection failed permanently. The event channel is
+	// closed afterwards.
+	ConnectionLost = "lost"
+)
+
+// ConnectionState is a synthetic event sent by the observer whenever the state
+// of its connection changes. It has the type connection_state.
+type ConnectionState struct {
+	EventBase
+	// State is one of ConnectionConnected, ConnectionReconnecting or
+	// ConnectionLost.
+	State string `json:"state"`
+	// Attempt is the number of the pending reconnect attempt.
+	Attempt int `json:"attempt,omitempty"`
+	// Resumed is set for a connected state after a reconnect. Events sent by
+	// the server in the meantime are missed, so any derived state should be
+	// refreshed.
+	Resumed bool `json:"resumed,omitempty"`
+	// Error describes why the connection failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ObserverOptions configures the connection of the observer.
+type ObserverOptions struct {
+	// DisableReconnect turns off automatic reconnects. The event channel is
+	// closed once the connection fails.
+	DisableReconnect bool
+	// MaxReconnects limits the number of consecutive reconnect attempts, zero
+	// for unlimited attempts.
+	MaxReconnects int
+	// MinBackoff is the delay before the first reconnect attempt. Defaults to
+	// 500ms.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between reconnect attempts. Defaults to
+	// 30s.
+	MaxBackoff time.Duration
+	// Timeout is the maximum time without any message from the server,
+	// including pings, before the connection is considered dead. It also
+	// limits the handshake. Defaults to 10s.
+	Timeout time.Duration
+	// BufferSize is the capacity of the event channel. A full channel stalls
+	// the connection, so slow consumers should use a larger buffer or a
+	// Broadcaster. Defaults to 1.
+	BufferSize int
+	// OnError is called with an *EventDecodeError for every message that
+	// cannot be decoded. Such messages are dropped. Without it, they are
+	// logged with the logger of the client, if any.
+	OnError func(err error)
+	// OnMessage is called with every event message as received, before it is
+	// decoded. Use it with EventRecorder.RecordMessage to record the stream
+	// including attributes unknown to the event structs.
+	OnMessage func(raw json.RawMessage)
+}
+
+func (o *ObserverOptions) withDefaults() ObserverOptions {
+	options := ObserverOptions{}
+	if o != nil {
+		options = *o
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = 500 * time.Millisecond
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = 30 * time.Second
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = options.MinBackoff
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = 1
+	}
+	return options
+}
+
 // Connect connects the observer and returns an eventInterface channel on success.
+// The observer reconnects automatically with the default ObserverOptions, see
+// ConnectWithOptions.
 func (os *ObserverService) Connect(ctx context.Context, roomID string) (<-chan EventInterface, error) {
-	headerFunc := func() (*http.Header, error) {
-		header := &http.Header{}
-		header.Set("Authorization", os.client.apiKey)
-		return header, nil
-	}
+	return os.ConnectWithOptions(ctx, roomID, nil)
+}
 
+// ConnectWithOptions connects the observer and returns an eventInterface
+// channel on success. If the connection fails, the observer reconnects with
+// exponential backoff and resubscribes to the room. Changes of the connection
+// are reported as ConnectionState events. The channel is closed when the
+// context is done or the connection is lost permanently.
+func (os *ObserverService) ConnectWithOptions(ctx context.Context, roomID string,
+	options *ObserverOptions) (<-chan EventInterface, error) {
 	baseURL := os.client.BaseURL
 	if baseURL == nil {
 		return nil, fmt.Errorf("Client-BaseURL not specified")
@@ -223,57 +363,121 @@ func (os *ObserverService) Connect(ctx context.Context, roomID string) (<-chan E
 		wsURL = strings.Replace(wsURL, "http", "ws", 1)
 	}
 
-	cable := ac.NewClient(wsURL, headerFunc)
-	ch, err := cable.Subscribe("RoomChannel")
+	opts := options.withDefaults()
+	conn, err := os.dialCable(ctx, wsURL, opts.Timeout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe: %s", err)
+		return nil, err
+	}
+
+	msgChan := make(chan EventInterface, opts.BufferSize)
+	go os.observe(ctx, roomID, wsURL, conn, opts, msgChan)
+	return msgChan, nil
+}
+
+// observe forwards the events of the connection to msgChan and reconnects
+// until the context is done or the connection is lost permanently.
+func (os *ObserverService) observe(ctx context.Context, roomID, wsURL string,
+	conn *websocket.Conn, opts ObserverOptions, msgChan chan<- EventInterface) {
+	defer close(msgChan)
+
+	send := func(ev EventInterface) bool {
+		select {
+		case msgChan <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	changeState := func(state ConnectionState) bool {
+		state.Type = "connection_state"
+		os.client.observerEvent(ctx, roomID, state.Type)
+		return send(&state)
 	}
 
-	msgChan := make(chan EventInterface, 1)
+	b := &backoff.Backoff{Min: opts.MinBackoff, Max: opts.MaxBackoff, Factor: 2, Jitter: true}
+	resumed := false
+	for {
+		onConfirm := func() bool {
+			b.Reset()
+			return changeState(ConnectionState{State: ConnectionConnected, Resumed: resumed})
+		}
+		onMessage := func(raw json.RawMessage) bool {
+			if opts.OnMessage != nil {
+				opts.OnMessage(raw)
+			}
+			ev, err := decodeEvent(raw)
+			if err != nil {
+				os.reportDecodeError(ctx, opts, err)
+				return true
+			}
+			os.client.observerEvent(ctx, roomID, ev.GetType())
+			return send(ev)
+		}
+		err := receiveCable(ctx, conn, opts.Timeout, onConfirm, onMessage)
+		conn.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		if opts.DisableReconnect || errors.Is(err, errSubscriptionRejected) ||
+			errors.Is(err, errDisconnected) {
+			changeState(ConnectionState{State: ConnectionLost, Error: err.Error()})
+			return
+		}
+		resumed = true
 
-	go func() {
-		for {
+		for conn = nil; conn == nil; {
+			attempt := int(b.Attempt()) + 1
+			if opts.MaxReconnects > 0 && attempt > opts.MaxReconnects {
+				changeState(ConnectionState{State: ConnectionLost, Error: err.Error()})
+				return
+			}
+			if !changeState(ConnectionState{State: ConnectionReconnecting,
+				Attempt: attempt, Error: err.Error()}) {
+				return
+			}
+			timer := time.NewTimer(b.Duration())
 			select {
 			case <-ctx.Done():
-				// try shutting down the channel
-				cable.Close()
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			conn, err = os.dialCable(ctx, wsURL, opts.Timeout)
+			if ctx.Err() != nil {
 				return
-			case ev, ok := <-ch:
-				if !ok {
-					return // fmt.Errorf("Read from channel failed. Probably closed")
-				}
-				if ev.Err != nil {
-					return //fmt.Errorf("Channel returned err: %s", ev.Err)
-				}
-				//fmt.Println("Message: ", ev.Event.Type, ev.Event.Data, string(ev.Event.Message))
-
-				if len(ev.Event.Message) == 0 {
-					continue
-				}
-
-				var msgBase EventBase
-				err := json.Unmarshal(ev.Event.Message, &msgBase)
-				if err != nil {
-					log.Printf("Failed to unmarshal [%s].\n", err)
-					continue
-				}
-				//log.Printf("message received. [%s].\n", ev.Event.Message)
-				msgInitFunc, ok := eventTypes[msgBase.Type]
-				if !ok {
-					log.Printf("Message-type %s not supported.", msgBase.Type)
-					continue
-				}
-				interf := msgInitFunc()
-				err = json.Unmarshal(ev.Event.Message, interf)
-				if err != nil {
-					log.Println("Failed to unmarshal.")
-					continue
-				}
-
-				msgChan <- interf
 			}
 		}
-	}()
+	}
+}
 
-	return msgChan, nil
+// decodeEvent decodes an event of the room channel. Events of unknown types
+// are provided as *RawEvent.
+func decodeEvent(raw json.RawMessage) (EventInterface, error) {
+	var msgBase EventBase
+	if err := json.Unmarshal(raw, &msgBase); err != nil {
+		return nil, &EventDecodeError{Raw: raw, Err: err}
+	}
+	eventTypesMu.RLock()
+	msgInitFunc, ok := eventTypes[msgBase.Type]
+	eventTypesMu.RUnlock()
+	if !ok {
+		return &RawEvent{EventBase: msgBase, Raw: raw}, nil
+	}
+	interf := msgInitFunc()
+	if err := json.Unmarshal(raw, interf); err != nil {
+		return nil, &EventDecodeError{Type: msgBase.Type, Raw: raw, Err: err}
+	}
+	return interf, nil
+}
+
+// reportDecodeError passes a decode error to the error callback or the logger
+// of the client.
+func (os *ObserverService) reportDecodeError(ctx context.Context, opts ObserverOptions, err error) {
+	if opts.OnError != nil {
+		opts.OnError(err)
+		return
+	}
+	if os.client.logger != nil && os.client.logger.Enabled(ctx, slog.LevelWarn) {
+		os.client.logger.Log(ctx, slog.LevelWarn, "eyeson observer event dropped", "error", err)
+	}
 }
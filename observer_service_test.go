@@ -0,0 +1,218 @@
+package eyeson_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"github.com/eyeson-team/eyeson-go/eyesontest"
+)
+
+func nextEvent(t *testing.T, ctx context.Context, events <-chan eyeson.EventInterface) eyeson.EventInterface {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("Event channel closed unexpectedly")
+		}
+		return ev
+	case <-ctx.Done():
+		t.Fatal("Timeout waiting for observer event")
+	}
+	return nil
+}
+
+func expectState(t *testing.T, ev eyeson.EventInterface, state string, resumed bool) {
+	t.Helper()
+	cs, ok := ev.(*eyeson.ConnectionState)
+	if !ok || cs.Type != "connection_state" || cs.State != state || cs.Resumed != resumed {
+		t.Fatalf("Expected connection state %s (resumed %v), got %#v", state, resumed, ev)
+	}
+}
+
+func TestObserverService_reconnect(t *testing.T) {
+	fake := eyesontest.NewServer("observer-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("observer-key", eyeson.WithCustomEndpoint(fake.URL))
+	if _, err := client.Rooms.Join("reconnect", "mike", nil); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.Observer.ConnectWithOptions(ctx, "reconnect",
+		&eyeson.ObserverOptions{MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	expectState(t, nextEvent(t, ctx, events), eyeson.ConnectionConnected, false)
+	if _, ok := nextEvent(t, ctx, events).(*eyeson.RoomUpdate); !ok {
+		t.Fatal("Expected initial room update")
+	}
+
+	fake.Disconnect("reconnect")
+	ev := nextEvent(t, ctx, events)
+	expectState(t, ev, eyeson.ConnectionReconnecting, false)
+	if cs := ev.(*eyeson.ConnectionState); cs.Attempt != 1 || cs.Error == "" {
+		t.Errorf("Expected first attempt with error, got %#v", cs)
+	}
+	expectState(t, nextEvent(t, ctx, events), eyeson.ConnectionConnected, true)
+	if _, ok := nextEvent(t, ctx, events).(*eyeson.RoomUpdate); !ok {
+		t.Fatal("Expected room update after resubscription")
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestObserverService_lost(t *testing.T) {
+	fake := eyesontest.NewServer("observer-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("observer-key", eyeson.WithCustomEndpoint(fake.URL))
+	if _, err := client.Rooms.Join("lost", "mike", nil); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.Observer.ConnectWithOptions(ctx, "lost",
+		&eyeson.ObserverOptions{DisableReconnect: true})
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	expectState(t, nextEvent(t, ctx, events), eyeson.ConnectionConnected, false)
+	nextEvent(t, ctx, events)
+
+	fake.Disconnect("lost")
+	expectState(t, nextEvent(t, ctx, events), eyeson.ConnectionLost, false)
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Expected channel to be closed")
+		}
+	case <-ctx.Done():
+		t.Error("Timeout waiting for channel to be closed")
+	}
+}
+
+func TestObserverService_connectFailure(t *testing.T) {
+	fake := eyesontest.NewServer("observer-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("observer-key", eyeson.WithCustomEndpoint(fake.URL))
+
+	if _, err := client.Observer.Connect(context.Background(), "unknown"); err == nil {
+		t.Error("Expected connect to an unknown room to fail")
+	}
+}
+
+type reactionEvent struct {
+	eyeson.EventBase
+	Emoji string `json:"emoji"`
+}
+
+func TestObserverService_unknownEvents(t *testing.T) {
+	eyeson.RegisterEventType("test_reaction", func() eyeson.EventInterface { return &reactionEvent{} })
+	fake := eyesontest.NewServer("observer-key")
+	defer fake.Close()
+	client, _ := eyeson.NewClient("observer-key", eyeson.WithCustomEndpoint(fake.URL))
+	if _, err := client.Rooms.Join("unknown", "mike", nil); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errs := make(chan error, 1)
+	var recorded bytes.Buffer
+	recorder := eyeson.NewEventRecorder(&recorded)
+	events, err := client.Observer.ConnectWithOptions(ctx, "unknown", &eyeson.ObserverOptions{
+		OnError:   func(err error) { errs <- err },
+		OnMessage: func(raw json.RawMessage) { recorder.RecordMessage(raw) },
+	})
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	nextEvent(t, ctx, events)
+	nextEvent(t, ctx, events)
+
+	send := func(raw string) {
+		ev := &eyeson.RawEvent{Raw: []byte(raw)}
+		if err := fake.SendEvent("unknown", ev); err != nil {
+			t.Fatalf("SendEvent failed: %v", err)
+		}
+	}
+	send(`{"type":"chat","content":42}`)
+	send(`{"type":"brand_new","value":1}`)
+	send(`{"type":"test_reaction","emoji":"+1"}`)
+
+	select {
+	case err := <-errs:
+		var decodeErr *eyeson.EventDecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Type != "chat" {
+			t.Errorf("Expected decode error for chat, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timeout waiting for decode error")
+	}
+	raw, ok := nextEvent(t, ctx, events).(*eyeson.RawEvent)
+	if !ok || raw.Type != "brand_new" || string(raw.Raw) != `{"type":"brand_new","value":1}` {
+		t.Errorf("Expected raw event, got %#v", raw)
+	}
+	if ev, ok := nextEvent(t, ctx, events).(*reactionEvent); !ok || ev.Emoji != "+1" {
+		t.Errorf("Expected registered event type, got %#v", ev)
+	}
+	for _, want := range []string{`"content":42`, `"value":1`, `"emoji":"+1"`} {
+		if !strings.Contains(recorded.String(), want) {
+			t.Errorf("Expected recorded messages to contain %s, got %s", want, recorded.String())
+		}
+	}
+}
+
+type wrappedTransport struct{ next http.RoundTripper }
+
+func (tr wrappedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return tr.next.RoundTrip(req)
+}
+
+func wrapTransport(next http.RoundTripper) http.RoundTripper {
+	return wrappedTransport{next}
+}
+
+func TestObserverService_customCAWithMiddleware(t *testing.T) {
+	fake := eyesontest.NewServer("observer-key")
+	defer fake.Close()
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+	client, err := eyeson.NewClient("observer-key", eyeson.WithCustomEndpoint(server.URL),
+		eyeson.WithCustomCAFile(caFile), eyeson.WithMiddleware(wrapTransport))
+	if err != nil {
+		t.Fatalf("Failed to init client: %v", err)
+	}
+	if _, err = client.Rooms.Join("tls", "mike", nil); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.Observer.Connect(ctx, "tls")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	expectState(t, nextEvent(t, ctx, events), eyeson.ConnectionConnected, false)
+}
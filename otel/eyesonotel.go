@@ -0,0 +1,120 @@
+// Package eyesonotel provides an eyeson.Instrumentation reporting API calls
+// as OpenTelemetry spans and metrics.
+//
+//	inst, err := eyesonotel.New()
+//	client, err := eyeson.NewClient(apiKey, eyeson.WithInstrumentation(inst))
+package eyesonotel
+
+import (
+	"context"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/eyeson-team/eyeson-go/otel"
+
+// Attribute keys used for spans and metrics.
+const (
+	OperationKey = attribute.Key("eyeson.operation")
+	RoomIDKey    = attribute.Key("eyeson.room_id")
+	EventTypeKey = attribute.Key("eyeson.event_type")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the Instrumentation.
+type Option func(*config)
+
+// WithTracerProvider Set the tracer provider instead of the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider Set the meter provider instead of the global one.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Instrumentation implements eyeson.Instrumentation. Every API call is
+// reported as client span named after the operation, e.g. user.set_layout,
+// and its duration is recorded in the histogram
+// eyeson.client.operation.duration. Observer events are counted in
+// eyeson.observer.events.
+type Instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	events   metric.Int64Counter
+}
+
+// New creates a new Instrumentation.
+func New(options ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram("eyeson.client.operation.duration",
+		metric.WithDescription("Duration of eyeson API calls"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	events, err := meter.Int64Counter("eyeson.observer.events",
+		metric.WithDescription("Number of received observer events"))
+	if err != nil {
+		return nil, err
+	}
+	return &Instrumentation{
+		tracer:   cfg.tracerProvider.Tracer(instrumentationName),
+		duration: duration,
+		events:   events,
+	}, nil
+}
+
+// StartOperation implements the eyeson.Instrumentation interface.
+func (i *Instrumentation) StartOperation(ctx context.Context,
+	op eyeson.Operation) (context.Context, func(eyeson.OperationResult)) {
+	attrs := []attribute.KeyValue{
+		OperationKey.String(op.Name),
+		attribute.String("http.request.method", op.Method),
+		attribute.String("url.path", op.Path),
+	}
+	if op.RoomID != "" {
+		attrs = append(attrs, RoomIDKey.String(op.RoomID))
+	}
+	ctx, span := i.tracer.Start(ctx, op.Name,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, func(result eyeson.OperationResult) {
+		metricAttrs := []attribute.KeyValue{OperationKey.String(op.Name)}
+		if result.StatusCode != 0 {
+			status := attribute.Int("http.response.status_code", result.StatusCode)
+			span.SetAttributes(status)
+			metricAttrs = append(metricAttrs, status)
+		}
+		if result.Err != nil {
+			span.RecordError(result.Err)
+			span.SetStatus(codes.Error, result.Err.Error())
+		}
+		span.End()
+		i.duration.Record(ctx, result.Duration.Seconds(), metric.WithAttributes(metricAttrs...))
+	}
+}
+
+// ObserverEvent implements the eyeson.Instrumentation interface.
+func (i *Instrumentation) ObserverEvent(ctx context.Context, event eyeson.ObserverEvent) {
+	i.events.Add(ctx, 1, metric.WithAttributes(EventTypeKey.String(event.Type)))
+}
@@ -0,0 +1,56 @@
+package eyesonotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	eyeson "github.com/eyeson-team/eyeson-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentation_StartOperation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	inst, err := New(WithTracerProvider(provider))
+	if err != nil {
+		t.Fatalf("Failed to create instrumentation: %v", err)
+	}
+	var _ eyeson.Instrumentation = inst
+
+	_, end := inst.StartOperation(context.Background(), eyeson.Operation{
+		Name: "user.set_layout", RoomID: "room-id", Method: "POST", Path: "/rooms/[REDACTED]/layout",
+	})
+	end(eyeson.OperationResult{StatusCode: 400, Duration: time.Millisecond, Err: errors.New("bad request")})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected one span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "user.set_layout" {
+		t.Errorf("Span name is %s, want user.set_layout", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Span status is %v, want error", span.Status().Code)
+	}
+	want := map[attribute.Key]attribute.Value{
+		RoomIDKey:                   attribute.StringValue("room-id"),
+		"http.response.status_code": attribute.IntValue(400),
+	}
+	for _, attr := range span.Attributes() {
+		if v, ok := want[attr.Key]; ok {
+			if v != attr.Value {
+				t.Errorf("Span attribute %s is %v, want %v", attr.Key, attr.Value.Emit(), v.Emit())
+			}
+			delete(want, attr.Key)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("Span is missing attributes %v", want)
+	}
+}
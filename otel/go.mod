@@ -0,0 +1,23 @@
+module github.com/eyeson-team/eyeson-go/otel
+
+go 1.21
+
+require (
+	github.com/eyeson-team/eyeson-go v0.0.0
+	go.opentelemetry.io/otel v1.29.0
+	go.opentelemetry.io/otel/metric v1.29.0
+	go.opentelemetry.io/otel/sdk v1.29.0
+	go.opentelemetry.io/otel/trace v1.29.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/gorilla/websocket v1.5.1 // indirect
+	github.com/jpillora/backoff v1.0.0 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+)
+
+replace github.com/eyeson-team/eyeson-go => ../
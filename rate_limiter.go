@@ -0,0 +1,208 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitExceeded is returned by Client.Do if a rate limiter in
+// RateLimitFailFast mode has no capacity left for a request.
+var ErrRateLimitExceeded = errors.New("Rate limit exceeded! The client side rate limit does not allow the request")
+
+// RouteClass groups API routes sharing a rate limit bucket.
+type RouteClass string
+
+// List of route classes used by ClassifyRoute.
+const (
+	// RouteDefault covers all routes without a specific class.
+	RouteDefault RouteClass = "default"
+	// RouteLayout covers layout and layer changes.
+	RouteLayout RouteClass = "layout"
+	// RouteMessages covers chat and custom messages.
+	RouteMessages RouteClass = "messages"
+	// RouteMedia covers recordings, broadcasts, playbacks, snapshots and
+	// forwards.
+	RouteMedia RouteClass = "media"
+)
+
+// RateLimitMode defines how a rate limiter handles requests exceeding the
+// limit.
+type RateLimitMode int
+
+const (
+	// RateLimitBlock waits until the request is allowed or its context is
+	// done.
+	RateLimitBlock RateLimitMode = iota
+	// RateLimitFailFast rejects the request with ErrRateLimitExceeded.
+	RateLimitFailFast
+)
+
+// RateLimit defines a token bucket refilled with Rate tokens per second and
+// holding at most Burst tokens. A zero Rate disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiterConfig configures a RateLimiter.
+type RateLimiterConfig struct {
+	// Default is the limit for all route classes not listed in Classes.
+	Default RateLimit
+	// Classes holds limits for specific route classes.
+	Classes map[RouteClass]RateLimit
+	// Mode defines the handling of requests exceeding the limit.
+	Mode RateLimitMode
+	// Classify maps a request to its route class. Defaults to ClassifyRoute.
+	Classify func(req *http.Request) RouteClass
+}
+
+// RateLimiter is a client-side token bucket limiter with one bucket per
+// route class. A single RateLimiter is shared by all services of a Client
+// and the user clients created from it.
+type RateLimiter struct {
+	config  RateLimiterConfig
+	mu      sync.Mutex
+	buckets map[RouteClass]*tokenBucket
+}
+
+// NewRateLimiter creates a new RateLimiter from the given config.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	if config.Classify == nil {
+		config.Classify = ClassifyRoute
+	}
+	return &RateLimiter{config: config, buckets: map[RouteClass]*tokenBucket{}}
+}
+
+// WithRateLimiter applies the given rate limiter to all requests of the
+// client.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// ClassifyRoute provides the route class of a request based on its path.
+func ClassifyRoute(req *http.Request) RouteClass {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		if i > 0 && (segments[i-1] == "rooms" || segments[i-1] == "guests") {
+			// skip room identifiers, access keys and guest tokens
+			continue
+		}
+		switch segment {
+		case "layout", "layers":
+			return RouteLayout
+		case "messages":
+			return RouteMessages
+		case "recording", "recordings", "broadcasts", "playbacks", "snapshot", "snapshots", "forward":
+			return RouteMedia
+		}
+	}
+	return RouteDefault
+}
+
+// Wait takes a token for the request, blocking or failing according to the
+// configured mode.
+func (l *RateLimiter) Wait(req *http.Request) error {
+	class := l.config.Classify(req)
+	bucket := l.bucket(class)
+	if bucket == nil {
+		return nil
+	}
+	if l.config.Mode == RateLimitFailFast {
+		if !bucket.take() {
+			return ErrRateLimitExceeded
+		}
+		return nil
+	}
+	return bucket.wait(req.Context())
+}
+
+func (l *RateLimiter) bucket(class RouteClass) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[class]; ok {
+		return b
+	}
+	limit, ok := l.config.Classes[class]
+	if !ok {
+		limit = l.config.Default
+	}
+	var b *tokenBucket
+	if limit.Rate > 0 {
+		b = newTokenBucket(limit)
+	}
+	l.buckets[class] = b
+	return b
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// refill adds the tokens accumulated since the last call. Must be called
+// with the lock held.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.refill(time.Now())
+	// reserve a token, going into debt if none is available
+	b.tokens--
+	delay := time.Duration(0)
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
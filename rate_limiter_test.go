@@ -0,0 +1,78 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClassifyRoute(t *testing.T) {
+	tests := map[string]RouteClass{
+		"/rooms":                  RouteDefault,
+		"/rooms/layout":           RouteDefault,
+		"/rooms/token/layout":     RouteLayout,
+		"/rooms/token/layers/1":   RouteLayout,
+		"/rooms/token/messages":   RouteMessages,
+		"/rooms/token/recording":  RouteMedia,
+		"/snapshots/42":           RouteMedia,
+		"/guests/messages":        RouteDefault,
+		"/rooms/id/forward/fw-id": RouteMedia,
+	}
+	for path, want := range tests {
+		req, _ := http.NewRequest("GET", "https://api.eyeson.team"+path, nil)
+		if got := ClassifyRoute(req); got != want {
+			t.Errorf("ClassifyRoute(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRateLimiter_failFast(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Classes: map[RouteClass]RateLimit{RouteMessages: {Rate: 0.001, Burst: 1}},
+		Mode:    RateLimitFailFast,
+	})
+	WithRateLimiter(limiter)(client)
+
+	mux.HandleFunc("/rooms/token/messages", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+	})
+	mux.HandleFunc("/rooms/token/layout", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+	})
+	user := &UserService{client: client.UserClient(), Data: &RoomResponse{AccessKey: "token"}}
+
+	if err := user.Chat("first"); err != nil {
+		t.Errorf("Expected first message to pass, got %v", err)
+	}
+	if err := user.Chat("second"); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("Expected ErrRateLimitExceeded, got %v", err)
+	}
+	if err := user.SetLayout(Auto, nil); err != nil {
+		t.Errorf("Expected layout bucket to be unlimited, got %v", err)
+	}
+}
+
+func TestRateLimiter_block(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{Default: RateLimit{Rate: 20, Burst: 1}})
+	req, _ := http.NewRequest("GET", "https://api.eyeson.team/rooms", nil)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(req); err != nil {
+			t.Fatalf("Wait returned unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected requests to be delayed, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(req.WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures the automatic retry of failed requests in
+// Client.Do. Requests are retried on transient status codes and on
+// connection errors, waiting with a jittered exponential backoff in between.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+	// MinBackoff is the wait time before the first retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponentially growing wait time between retries.
+	MaxBackoff time.Duration
+	// MaxRetryAfter caps the wait time requested by a Retry-After header,
+	// one minute if zero.
+	MaxRetryAfter time.Duration
+	// StatusCodes lists the response status codes that are retried. If empty,
+	// 429, 502, 503 and 504 are retried.
+	StatusCodes []int
+	// Retryable decides whether a request may be retried at all. If nil, only
+	// idempotent methods and requests with a context created by
+	// ContextWithRetry are retried.
+	Retryable func(req *http.Request) bool
+}
+
+// DefaultRetryPolicy provides a policy with three retries, starting with a
+// backoff of 500ms up to 10s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+	}
+}
+
+// WithRetryPolicy enables automatic retries of failed requests using the
+// given policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+type retryContextKey struct{}
+
+// ContextWithRetry marks all requests made with the returned context as
+// retryable, even if their method is not idempotent. Use it to opt in
+// specific POST requests like StartRecordingContext.
+func ContextWithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryContextKey{}, true)
+}
+
+// defaultMaxRetryAfter caps Retry-After headers if MaxRetryAfter is zero.
+const defaultMaxRetryAfter = time.Minute
+
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p *RetryPolicy) retryable(req *http.Request) bool {
+	if p.Retryable != nil {
+		return p.Retryable(req)
+	}
+	if marked, _ := req.Context().Value(retryContextKey{}).(bool); marked {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryStatus(code int) bool {
+	codes := p.StatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryStatusCodes
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry reports whether the outcome of an attempt is worth retrying.
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.MaxRetries || req.Context().Err() != nil || !p.retryable(req) {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body cannot be rewound for another attempt
+		return false
+	}
+	if err != nil {
+		return isTransientError(err)
+	}
+	return p.retryStatus(resp.StatusCode)
+}
+
+// backoff calculates the wait time before the next attempt. A Retry-After
+// header of the response takes precedence if it asks for a longer wait, up to
+// MaxRetryAfter.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := p.MinBackoff
+	for i := 0; i < attempt && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait > 0 {
+		// equal jitter: keep half of the wait time and randomize the rest
+		wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	}
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok && after > wait {
+			limit := p.MaxRetryAfter
+			if limit <= 0 {
+				limit = defaultMaxRetryAfter
+			}
+			if after > limit {
+				after = limit
+			}
+			wait = after
+		}
+	}
+	return wait
+}
+
+// parseRetryAfter parses the value of a Retry-After header given either in
+// seconds or as HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// send sends a single attempt of the request, waiting for the rate limiter
+// of the client first and logging the outcome.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(req); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if c.logger != nil {
+		c.logRequest(req, resp, err, start)
+	}
+	return resp, err
+}
+
+// sendWithRetry sends the request and retries it according to the retry
+// policy of the client. The returned response has an unread body.
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy
+	if policy == nil {
+		return c.send(req)
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(req)
+		if !policy.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+		wait := policy.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		req = req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func retryTestPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestDo_retryTransientStatus(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	WithRetryPolicy(retryTestPolicy())(client)
+
+	calls := 0
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	if _, err := client.Rooms.GetCurrentMeetings(); err != nil {
+		t.Errorf("Expected request to succeed after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDo_retryExhausted(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	WithRetryPolicy(retryTestPolicy())(client)
+
+	calls := 0
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(429)
+	})
+
+	_, err := client.Rooms.GetCurrentMeetings()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDo_retryPostOptIn(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	WithRetryPolicy(retryTestPolicy())(client)
+
+	calls := 0
+	mux.HandleFunc("/rooms/token/messages", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		testFormValues(t, r, values{"type": "chat", "content": "hello"})
+		if calls == 1 {
+			w.WriteHeader(502)
+			return
+		}
+		w.WriteHeader(201)
+	})
+	user := &UserService{client: client.UserClient(), Data: &RoomResponse{AccessKey: "token"}}
+
+	if err := user.Chat("hello"); !errors.Is(err, ErrServer) {
+		t.Errorf("Expected POST not to be retried by default, got %v", err)
+	}
+	calls = 0
+	if err := user.ChatContext(ContextWithRetry(context.Background()), "hello"); err != nil {
+		t.Errorf("Expected opted in POST to be retried, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetryPolicy_retryAfter(t *testing.T) {
+	policy := retryTestPolicy()
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "2")
+	if got := policy.backoff(0, resp); got != 2*time.Second {
+		t.Errorf("Expected backoff of Retry-After 2s, got %v", got)
+	}
+	if got := policy.backoff(5, &http.Response{Header: http.Header{}}); got > policy.MaxBackoff {
+		t.Errorf("Expected backoff capped at %v, got %v", policy.MaxBackoff, got)
+	}
+	resp.Header.Set("Retry-After", "86400")
+	if got := policy.backoff(0, resp); got != time.Minute {
+		t.Errorf("Expected Retry-After capped at 1m, got %v", got)
+	}
+	policy.MaxRetryAfter = 3 * time.Second
+	if got := policy.backoff(0, resp); got != 3*time.Second {
+		t.Errorf("Expected Retry-After capped at MaxRetryAfter 3s, got %v", got)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("Expected invalid Retry-After to be ignored")
+	}
+}
@@ -0,0 +1,339 @@
+package eyeson
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ChangeKind names a change of the room state.
+type ChangeKind string
+
+// Changes reported by RoomState.
+const (
+	// ChangeRoom a property of the room like ready or shutdown changed.
+	ChangeRoom ChangeKind = "room"
+	// ChangeOptions the room options changed.
+	ChangeOptions ChangeKind = "options"
+	// ChangeParticipantOnline a participant joined or came back online.
+	ChangeParticipantOnline ChangeKind = "participant_online"
+	// ChangeParticipantOffline a participant went offline or left.
+	ChangeParticipantOffline ChangeKind = "participant_offline"
+	// ChangeParticipantUpdated a property of a participant changed.
+	ChangeParticipantUpdated ChangeKind = "participant_updated"
+	// ChangePodium the podium layout or positions changed.
+	ChangePodium ChangeKind = "podium"
+	// ChangeRecordingStarted a recording has been started.
+	ChangeRecordingStarted ChangeKind = "recording_started"
+	// ChangeRecordingStopped the active recording has been stopped.
+	ChangeRecordingStopped ChangeKind = "recording_stopped"
+	// ChangePlayback the running playbacks changed.
+	ChangePlayback ChangeKind = "playback"
+	// ChangeBroadcasts the live-stream broadcasts changed.
+	ChangeBroadcasts ChangeKind = "broadcasts"
+)
+
+// RoomChange describes a single change of the room state.
+type RoomChange struct {
+	Kind ChangeKind
+	// ParticipantID identifies the participant of participant changes.
+	ParticipantID string
+	// Before is the state prior to the change.
+	Before RoomStateSnapshot
+	// After is the state including the change.
+	After RoomStateSnapshot
+	// Event is the event causing the change, nil for changes computed by
+	// DiffRoomState.
+	Event EventInterface
+}
+
+// RoomStateSnapshot is a copy of the room state at a point in time.
+type RoomStateSnapshot struct {
+	Room    EventRoom
+	Options Options
+	// Participants holds all known participants by their id, including
+	// offline ones.
+	Participants map[string]Participant
+	Podium       []PodiumPosition
+	// Recording is the active recording, nil if not recording.
+	Recording  *Recording
+	Playing    []Playback
+	Broadcasts []Broadcast
+}
+
+// OnlineParticipants provides all online participants ordered by id.
+func (s RoomStateSnapshot) OnlineParticipants() []Participant {
+	participants := []Participant{}
+	for _, p := range s.Participants {
+		if p.Online {
+			participants = append(participants, p)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i].ID < participants[j].ID })
+	return participants
+}
+
+// IsRecording reports whether a recording is active.
+func (s RoomStateSnapshot) IsRecording() bool {
+	return s.Recording != nil
+}
+
+func (s RoomStateSnapshot) clone() RoomStateSnapshot {
+	c := s
+	c.Room.Participants = append([]Participant(nil), s.Room.Participants...)
+	c.Room.Broadcasts = append([]Broadcast(nil), s.Room.Broadcasts...)
+	c.Participants = make(map[string]Participant, len(s.Participants))
+	for id, p := range s.Participants {
+		c.Participants[id] = p
+	}
+	c.Podium = append([]PodiumPosition(nil), s.Podium...)
+	if s.Recording != nil {
+		recording := *s.Recording
+		c.Recording = &recording
+	}
+	c.Playing = append([]Playback(nil), s.Playing...)
+	c.Broadcasts = append([]Broadcast(nil), s.Broadcasts...)
+	return c
+}
+
+// DiffRoomState computes the changes between two snapshots of a room state.
+func DiffRoomState(before, after RoomStateSnapshot) []RoomChange {
+	changes := []RoomChange{}
+	add := func(kind ChangeKind, participantID string) {
+		changes = append(changes, RoomChange{Kind: kind, ParticipantID: participantID,
+			Before: before, After: after})
+	}
+
+	if before.Room.ID != after.Room.ID || before.Room.Name != after.Room.Name ||
+		before.Room.Ready != after.Room.Ready || before.Room.Shutdown != after.Room.Shutdown ||
+		!before.Room.StartedAt.Equal(after.Room.StartedAt) ||
+		before.Room.GuestToken != after.Room.GuestToken {
+		add(ChangeRoom, "")
+	}
+	if before.Options != after.Options {
+		add(ChangeOptions, "")
+	}
+
+	ids := []string{}
+	for id := range before.Participants {
+		ids = append(ids, id)
+	}
+	for id := range after.Participants {
+		if _, ok := before.Participants[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		old, existed := before.Participants[id]
+		p, exists := after.Participants[id]
+		wasOnline := existed && old.Online
+		isOnline := exists && p.Online
+		switch {
+		case !wasOnline && isOnline:
+			add(ChangeParticipantOnline, id)
+		case wasOnline && !isOnline:
+			add(ChangeParticipantOffline, id)
+		case existed && exists && old != p:
+			add(ChangeParticipantUpdated, id)
+		}
+	}
+
+	if !reflect.DeepEqual(before.Podium, after.Podium) {
+		add(ChangePodium, "")
+	}
+	switch {
+	case before.Recording == nil && after.Recording != nil:
+		add(ChangeRecordingStarted, "")
+	case before.Recording != nil && after.Recording == nil:
+		add(ChangeRecordingStopped, "")
+	case before.Recording != nil && before.Recording.ID != after.Recording.ID:
+		add(ChangeRecordingStopped, "")
+		add(ChangeRecordingStarted, "")
+	}
+	if !reflect.DeepEqual(before.Playing, after.Playing) {
+		add(ChangePlayback, "")
+	}
+	if !reflect.DeepEqual(before.Broadcasts, after.Broadcasts) {
+		add(ChangeBroadcasts, "")
+	}
+	return changes
+}
+
+// ChangeFilter selects the changes passed to a subscriber.
+type ChangeFilter func(change RoomChange) bool
+
+// ChangeOf selects changes of the given kinds.
+func ChangeOf(kinds ...ChangeKind) ChangeFilter {
+	return func(change RoomChange) bool {
+		for _, kind := range kinds {
+			if change.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ParticipantChange selects changes of a single participant, optionally
+// limited to the given kinds, e.g. to get notified when a user goes offline.
+func ParticipantChange(participantID string, kinds ...ChangeKind) ChangeFilter {
+	return func(change RoomChange) bool {
+		if change.ParticipantID != participantID {
+			return false
+		}
+		return len(kinds) == 0 || ChangeOf(kinds...)(change)
+	}
+}
+
+type stateSubscription struct {
+	filter  ChangeFilter
+	handler func(RoomChange)
+}
+
+// RoomState maintains the state of a room from observer events. It is safe
+// for concurrent use.
+type RoomState struct {
+	mu            sync.RWMutex
+	state         RoomStateSnapshot
+	subscriptions map[int]stateSubscription
+	nextID        int
+	// notifyMu keeps notifications in order of the applied events.
+	notifyMu sync.Mutex
+}
+
+// NewRoomState creates an empty room state.
+func NewRoomState() *RoomState {
+	return &RoomState{
+		state:         RoomStateSnapshot{Participants: map[string]Participant{}},
+		subscriptions: map[int]stateSubscription{},
+	}
+}
+
+// Snapshot provides a copy of the current state.
+func (s *RoomState) Snapshot() RoomStateSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.clone()
+}
+
+// Participant provides a participant by its id.
+func (s *RoomState) Participant(id string) (Participant, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.state.Participants[id]
+	return p, ok
+}
+
+// IsRecording reports whether a recording is active.
+func (s *RoomState) IsRecording() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.Recording != nil
+}
+
+// Subscribe registers a handler called for every change matching the filter,
+// or every change if the filter is nil. Handlers are called in order of the
+// changes from the goroutine applying the events and must not block. The
+// returned function removes the subscription.
+func (s *RoomState) Subscribe(filter ChangeFilter, handler func(RoomChange)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.subscriptions[id] = stateSubscription{filter: filter, handler: handler}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscriptions, id)
+	}
+}
+
+// Apply updates the state with an event and returns the resulting changes.
+// Events not affecting the room state are ignored.
+func (s *RoomState) Apply(event EventInterface) []RoomChange {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+
+	s.mu.Lock()
+	before := s.state.clone()
+	if !reduceRoomState(&s.state, event) {
+		s.mu.Unlock()
+		return nil
+	}
+	after := s.state.clone()
+	subscriptions := make([]stateSubscription, 0, len(s.subscriptions))
+	ids := make([]int, 0, len(s.subscriptions))
+	for id := range s.subscriptions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		subscriptions = append(subscriptions, s.subscriptions[id])
+	}
+	s.mu.Unlock()
+
+	changes := DiffRoomState(before, after)
+	for i := range changes {
+		changes[i].Event = event
+		for _, sub := range subscriptions {
+			if sub.filter == nil || sub.filter(changes[i]) {
+				sub.handler(changes[i])
+			}
+		}
+	}
+	return changes
+}
+
+// Run applies all events of the channel until the context is done or the
+// channel is closed.
+func (s *RoomState) Run(ctx context.Context, events <-chan EventInterface) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			s.Apply(event)
+		}
+	}
+}
+
+// reduceRoomState applies an event to the state and reports whether the
+// event affects the state at all.
+func reduceRoomState(state *RoomStateSnapshot, event EventInterface) bool {
+	switch ev := event.(type) {
+	case *RoomUpdate:
+		state.Room = ev.Content
+		state.Options = ev.Content.Options
+		state.Broadcasts = append([]Broadcast(nil), ev.Content.Broadcasts...)
+		// the room update lists all participants, e.g. after a reconnect
+		state.Participants = make(map[string]Participant, len(ev.Content.Participants))
+		for _, p := range ev.Content.Participants {
+			state.Participants[p.ID] = p
+		}
+	case *ParticipantUpdate:
+		state.Participants[ev.Participant.ID] = ev.Participant
+	case *PodiumUpdate:
+		state.Podium = append([]PodiumPosition(nil), ev.Podium...)
+	case *RecordingUpdate:
+		if ev.Recording.Duration == nil && ev.Recording.Links.Download == nil {
+			recording := ev.Recording
+			state.Recording = &recording
+		} else if state.Recording != nil && state.Recording.ID == ev.Recording.ID {
+			state.Recording = nil
+		}
+	case *PlaybackUpdate:
+		state.Playing = append([]Playback(nil), ev.Playing...)
+	case *BroadcastUpdate:
+		state.Broadcasts = append([]Broadcast(nil), ev.Broadcasts...)
+	case *OptionsUpdate:
+		state.Options = ev.Options
+	default:
+		return false
+	}
+	return true
+}
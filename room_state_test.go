@@ -0,0 +1,97 @@
+package eyeson
+
+import (
+	"context"
+	"testing"
+)
+
+func roomUpdateEvent(participants ...Participant) *RoomUpdate {
+	return &RoomUpdate{EventBase: EventBase{Type: "room_update"},
+		Content: EventRoom{ID: "room", Name: "Room", Ready: true, Participants: participants}}
+}
+
+func participantEvent(id string, online bool) *ParticipantUpdate {
+	return &ParticipantUpdate{EventBase: EventBase{Type: "participant_update"},
+		Participant: Participant{ID: id, Name: id, Online: online}}
+}
+
+func TestRoomState_apply(t *testing.T) {
+	state := NewRoomState()
+	changes := state.Apply(roomUpdateEvent(Participant{ID: "a", Online: true}))
+	if len(changes) != 2 || changes[0].Kind != ChangeRoom || changes[1].Kind != ChangeParticipantOnline {
+		t.Fatalf("Unexpected changes %+v", changes)
+	}
+
+	state.Apply(participantEvent("b", true))
+	state.Apply(&PodiumUpdate{EventBase: EventBase{Type: "podium_update"},
+		Podium: []PodiumPosition{{UserID: "a"}}})
+	changes = state.Apply(&RecordingUpdate{EventBase: EventBase{Type: "recording_update"},
+		Recording: Recording{ID: "rec"}})
+	if len(changes) != 1 || changes[0].Kind != ChangeRecordingStarted || changes[0].Event == nil {
+		t.Errorf("Expected recording started, got %+v", changes)
+	}
+	if changes := state.Apply(&Chat{EventBase: EventBase{Type: "chat"}}); changes != nil {
+		t.Errorf("Expected chat to be ignored, got %+v", changes)
+	}
+
+	snapshot := state.Snapshot()
+	if online := snapshot.OnlineParticipants(); len(online) != 2 || online[0].ID != "a" || online[1].ID != "b" {
+		t.Errorf("Expected two online participants, got %+v", online)
+	}
+	if !snapshot.IsRecording() || len(snapshot.Podium) != 1 {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+	snapshot.Participants["a"] = Participant{ID: "a"}
+	if p, _ := state.Participant("a"); !p.Online {
+		t.Error("Expected snapshot to be a copy")
+	}
+
+	duration := 10
+	state.Apply(&RecordingUpdate{EventBase: EventBase{Type: "recording_update"},
+		Recording: Recording{ID: "rec", Duration: &duration}})
+	if state.IsRecording() {
+		t.Error("Expected recording to be stopped")
+	}
+}
+
+func TestRoomState_roomUpdateReplacesParticipants(t *testing.T) {
+	state := NewRoomState()
+	state.Apply(roomUpdateEvent(Participant{ID: "a", Online: true}, Participant{ID: "b", Online: true}))
+	changes := state.Apply(roomUpdateEvent(Participant{ID: "b", Online: true}))
+	if len(changes) != 1 || changes[0].Kind != ChangeParticipantOffline || changes[0].ParticipantID != "a" {
+		t.Errorf("Expected participant a to go offline, got %+v", changes)
+	}
+}
+
+func TestRoomState_subscribe(t *testing.T) {
+	state := NewRoomState()
+	var offline []RoomChange
+	unsubscribe := state.Subscribe(ParticipantChange("a", ChangeParticipantOffline), func(c RoomChange) {
+		offline = append(offline, c)
+	})
+	var all int
+	state.Subscribe(nil, func(c RoomChange) { all++ })
+
+	events := make(chan EventInterface, 4)
+	events <- participantEvent("a", true)
+	events <- participantEvent("b", true)
+	events <- participantEvent("b", false)
+	events <- participantEvent("a", false)
+	close(events)
+	if err := state.Run(context.Background(), events); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(offline) != 1 || !offline[0].Before.Participants["a"].Online || offline[0].After.Participants["a"].Online {
+		t.Errorf("Expected a single offline change of a, got %+v", offline)
+	}
+	if all != 4 {
+		t.Errorf("Expected 4 changes, got %d", all)
+	}
+
+	unsubscribe()
+	state.Apply(participantEvent("a", true))
+	state.Apply(participantEvent("a", false))
+	if len(offline) != 1 {
+		t.Error("Expected no notification after unsubscribe")
+	}
+}
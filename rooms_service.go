@@ -1,6 +1,7 @@
 package eyeson
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -17,15 +18,53 @@ type RoomsService service
 // omitted, the eyeson-api will therefor create a new room for every user
 // joining.
 func (srv *RoomsService) Join(id string, user string, options map[string]string) (*UserService, error) {
+	return srv.JoinContext(context.Background(), id, user, options)
+}
+
+// JoinContext is like Join but uses the given context for the request.
+func (srv *RoomsService) JoinContext(ctx context.Context, id string, user string,
+	options map[string]string) (*UserService, error) {
+	data := url.Values{}
+	for k, v := range options {
+		data.Set(k, v)
+	}
+	return srv.join(ctx, id, user, data)
+}
+
+// JoinWithOptions is like Join but takes typed options, which are validated
+// before the request is sent.
+func (srv *RoomsService) JoinWithOptions(id string, user string, options *JoinOptions) (*UserService, error) {
+	return srv.JoinWithOptionsContext(context.Background(), id, user, options)
+}
+
+// JoinWithOptionsContext is like JoinWithOptions but uses the given context
+// for the request.
+func (srv *RoomsService) JoinWithOptionsContext(ctx context.Context, id string, user string,
+	options *JoinOptions) (*UserService, error) {
+	var data url.Values
+	if options != nil {
+		var err error
+		if data, err = options.Encode(); err != nil {
+			return nil, err
+		}
+	}
+	return srv.join(ctx, id, user, data)
+}
+
+// join starts and joins a meeting, the given options take precedence over
+// the room identifier and user name.
+func (srv *RoomsService) join(ctx context.Context, id string, user string,
+	options url.Values) (*UserService, error) {
+	ctx = withOperation(ctx, "rooms.join", id)
 	data := url.Values{}
 	if id != "" {
 		data.Set("id", id)
 	}
 	data.Set("user[name]", user)
 	for k, v := range options {
-		data.Set(k, v)
+		data[k] = v
 	}
-	req, err := srv.client.NewRequest(http.MethodPost, "/rooms", data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodPost, "/rooms", data)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +78,14 @@ func (srv *RoomsService) Join(id string, user string, options map[string]string)
 
 // GuestJoin creates a new guest user for an active meeting.
 func (srv *RoomsService) GuestJoin(guestToken, id, name, avatar string) (*UserService, error) {
+	return srv.GuestJoinContext(context.Background(), guestToken, id, name, avatar)
+}
+
+// GuestJoinContext is like GuestJoin but uses the given context for the
+// request.
+func (srv *RoomsService) GuestJoinContext(ctx context.Context, guestToken, id, name,
+	avatar string) (*UserService, error) {
+	ctx = withOperation(ctx, "rooms.guest_join", id)
 	data := url.Values{}
 	data.Set("name", name)
 	if id != "" {
@@ -47,7 +94,7 @@ func (srv *RoomsService) GuestJoin(guestToken, id, name, avatar string) (*UserSe
 	if avatar != "" {
 		data.Set("avatar", avatar)
 	}
-	req, err := srv.client.NewRequest(http.MethodPost, "/guests/"+guestToken, data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodPost, "/guests/"+guestToken, data)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +108,13 @@ func (srv *RoomsService) GuestJoin(guestToken, id, name, avatar string) (*UserSe
 
 // Shutdown force stops a running meeting.
 func (srv *RoomsService) Shutdown(id string) error {
-	req, err := srv.client.NewRequest(http.MethodDelete, "/rooms/"+id, nil)
+	return srv.ShutdownContext(context.Background(), id)
+}
+
+// ShutdownContext is like Shutdown but uses the given context for the request.
+func (srv *RoomsService) ShutdownContext(ctx context.Context, id string) error {
+	ctx = withOperation(ctx, "rooms.shutdown", id)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodDelete, "/rooms/"+id, nil)
 	if err != nil {
 		return err
 	}
@@ -75,6 +128,14 @@ func (srv *RoomsService) Shutdown(id string) error {
 // ForwardSource starts forwarding the userID media to the specified url.
 func (srv *RoomsService) ForwardSource(id string, forwardID string, userID string,
 	mediaTypes []MediaType, destURL string) error {
+	return srv.ForwardSourceContext(context.Background(), id, forwardID, userID, mediaTypes, destURL)
+}
+
+// ForwardSourceContext is like ForwardSource but uses the given context for
+// the request.
+func (srv *RoomsService) ForwardSourceContext(ctx context.Context, id string, forwardID string,
+	userID string, mediaTypes []MediaType, destURL string) error {
+	ctx = withOperation(ctx, "rooms.forward_source", id)
 	data := url.Values{}
 	data.Set("forward_id", forwardID)
 	data.Set("user_id", userID)
@@ -85,7 +146,7 @@ func (srv *RoomsService) ForwardSource(id string, forwardID string, userID strin
 	}
 	data.Set("type", strings.Join(mediaTypesStrings, ","))
 
-	req, err := srv.client.NewRequest(http.MethodPost, "/rooms/"+id+"/forward/source", data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodPost, "/rooms/"+id+"/forward/source", data)
 	if err != nil {
 		return err
 	}
@@ -98,7 +159,14 @@ func (srv *RoomsService) ForwardSource(id string, forwardID string, userID strin
 
 // DeleteForward deletes a forward by its forwardID
 func (srv *RoomsService) DeleteForward(id string, forwardID string) error {
-	req, err := srv.client.NewRequest(http.MethodDelete, "/rooms/"+id+"/forward/"+forwardID, nil)
+	return srv.DeleteForwardContext(context.Background(), id, forwardID)
+}
+
+// DeleteForwardContext is like DeleteForward but uses the given context for
+// the request.
+func (srv *RoomsService) DeleteForwardContext(ctx context.Context, id string, forwardID string) error {
+	ctx = withOperation(ctx, "rooms.delete_forward", id)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodDelete, "/rooms/"+id+"/forward/"+forwardID, nil)
 	if err != nil {
 		return err
 	}
@@ -111,8 +179,15 @@ func (srv *RoomsService) DeleteForward(id string, forwardID string) error {
 
 // GetSnapshot retrieves a snapshot.
 func (srv *RoomsService) GetSnapshot(snapshotID string) (*Snapshot, error) {
+	return srv.GetSnapshotContext(context.Background(), snapshotID)
+}
+
+// GetSnapshotContext is like GetSnapshot but uses the given context for the
+// request.
+func (srv *RoomsService) GetSnapshotContext(ctx context.Context, snapshotID string) (*Snapshot, error) {
+	ctx = withOperation(ctx, "rooms.get_snapshot", "")
 	path := "/snapshots/" + snapshotID
-	req, err := srv.client.NewRequest(http.MethodGet, path, nil)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +209,14 @@ type GetSnaphostsOptions struct {
 
 // GetSnapshots retrieves a a list of snapshots for a room.
 func (srv *RoomsService) GetSnapshots(ID string, options *GetSnaphostsOptions) (*[]Snapshot, error) {
+	return srv.GetSnapshotsContext(context.Background(), ID, options)
+}
+
+// GetSnapshotsContext is like GetSnapshots but uses the given context for the
+// request.
+func (srv *RoomsService) GetSnapshotsContext(ctx context.Context, ID string,
+	options *GetSnaphostsOptions) (*[]Snapshot, error) {
+	ctx = withOperation(ctx, "rooms.get_snapshots", ID)
 	data := url.Values{}
 	if options != nil {
 		if options.Page != nil {
@@ -150,7 +233,7 @@ func (srv *RoomsService) GetSnapshots(ID string, options *GetSnaphostsOptions) (
 		}
 	}
 	path := "/rooms/" + ID + "/snapshots"
-	req, err := srv.client.NewRequest(http.MethodGet, path, data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, path, data)
 	if err != nil {
 		return nil, err
 	}
@@ -164,8 +247,15 @@ func (srv *RoomsService) GetSnapshots(ID string, options *GetSnaphostsOptions) (
 
 // DeleteSnapshot deletes a snapshot.
 func (u *RoomsService) DeleteSnapshot(snapshotID string) error {
+	return u.DeleteSnapshotContext(context.Background(), snapshotID)
+}
+
+// DeleteSnapshotContext is like DeleteSnapshot but uses the given context for
+// the request.
+func (u *RoomsService) DeleteSnapshotContext(ctx context.Context, snapshotID string) error {
+	ctx = withOperation(ctx, "rooms.delete_snapshot", "")
 	path := "/snapshots/" + snapshotID
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -178,8 +268,15 @@ func (u *RoomsService) DeleteSnapshot(snapshotID string) error {
 
 // GetRecording retrieves a recording.
 func (srv *RoomsService) GetRecording(recordingID string) (*Recording, error) {
+	return srv.GetRecordingContext(context.Background(), recordingID)
+}
+
+// GetRecordingContext is like GetRecording but uses the given context for the
+// request.
+func (srv *RoomsService) GetRecordingContext(ctx context.Context, recordingID string) (*Recording, error) {
+	ctx = withOperation(ctx, "rooms.get_recording", "")
 	path := "/recordings/" + recordingID
-	req, err := srv.client.NewRequest(http.MethodGet, path, nil)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -201,6 +298,14 @@ type GetRecordingsOptions struct {
 
 // GetSnapshots retrieves a a list of recordings for a room.
 func (srv *RoomsService) GetRecordings(ID string, options *GetRecordingsOptions) (*[]Recording, error) {
+	return srv.GetRecordingsContext(context.Background(), ID, options)
+}
+
+// GetRecordingsContext is like GetRecordings but uses the given context for
+// the request.
+func (srv *RoomsService) GetRecordingsContext(ctx context.Context, ID string,
+	options *GetRecordingsOptions) (*[]Recording, error) {
+	ctx = withOperation(ctx, "rooms.get_recordings", ID)
 	data := url.Values{}
 	if options != nil {
 		if options.Page != nil {
@@ -217,7 +322,7 @@ func (srv *RoomsService) GetRecordings(ID string, options *GetRecordingsOptions)
 		}
 	}
 	path := "/rooms/" + ID + "/recordings"
-	req, err := srv.client.NewRequest(http.MethodGet, path, data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, path, data)
 	if err != nil {
 		return nil, err
 	}
@@ -231,8 +336,15 @@ func (srv *RoomsService) GetRecordings(ID string, options *GetRecordingsOptions)
 
 // DeleteRecording deletes a recording.
 func (u *RoomsService) DeleteRecording(recordingID string) error {
+	return u.DeleteRecordingContext(context.Background(), recordingID)
+}
+
+// DeleteRecordingContext is like DeleteRecording but uses the given context
+// for the request.
+func (u *RoomsService) DeleteRecordingContext(ctx context.Context, recordingID string) error {
+	ctx = withOperation(ctx, "rooms.delete_recording", "")
 	path := "/recordings/" + recordingID
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -243,8 +355,16 @@ func (u *RoomsService) DeleteRecording(recordingID string) error {
 	return validateResponse(resp)
 }
 
+// GetCurrentMeetings lists all meetings currently running.
 func (srv *RoomsService) GetCurrentMeetings() (*[]RoomInfo, error) {
-	req, err := srv.client.NewRequest(http.MethodGet, "/rooms", nil)
+	return srv.GetCurrentMeetingsContext(context.Background())
+}
+
+// GetCurrentMeetingsContext is like GetCurrentMeetings but uses the given
+// context for the request.
+func (srv *RoomsService) GetCurrentMeetingsContext(ctx context.Context) (*[]RoomInfo, error) {
+	ctx = withOperation(ctx, "rooms.get_current_meetings", "")
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, "/rooms", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -257,13 +377,23 @@ func (srv *RoomsService) GetCurrentMeetings() (*[]RoomInfo, error) {
 	return &rooms, validateResponse(resp)
 }
 
+// GetRoomUsers lists the participants of a room, optionally filtered by their
+// online status.
 func (srv *RoomsService) GetRoomUsers(ID string, online *bool) (*[]Participant, error) {
+	return srv.GetRoomUsersContext(context.Background(), ID, online)
+}
+
+// GetRoomUsersContext is like GetRoomUsers but uses the given context for the
+// request.
+func (srv *RoomsService) GetRoomUsersContext(ctx context.Context, ID string,
+	online *bool) (*[]Participant, error) {
+	ctx = withOperation(ctx, "rooms.get_room_users", ID)
 	data := url.Values{}
 	if online != nil {
 		data.Set("online", strconv.FormatBool(*online))
 	}
 	path := "/rooms/" + ID + "/users"
-	req, err := srv.client.NewRequest(http.MethodGet, path, data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, path, data)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package eyeson
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -29,6 +31,24 @@ func TestRoomsService_Join(t *testing.T) {
 	}
 }
 
+func TestRoomsService_JoinContext(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	done := make(chan struct{})
+	defer close(done)
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Rooms.JoinContext(ctx, "", "mike@eyeson.team", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoomsService JoinContext expected deadline exceeded, got %v", err)
+	}
+}
+
 func TestRoomsService_GuestJoin(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()
@@ -69,37 +69,38 @@ func NewUserServiceFromAccessKey(accessKey string, options ...ClientOption) (*Us
 // fixed polling interval of one second. WaitReady responds with an error on
 // timeout or any communication problems.
 func (u *UserService) WaitReady() error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(Timeout)*time.Second)
+	return u.WaitReadyContext(context.Background())
+}
+
+// WaitReadyContext is like WaitReady but stops waiting as soon as the given
+// context is done. The Timeout applies in addition to any context deadline.
+func (u *UserService) WaitReadyContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(Timeout)*time.Second)
 	defer cancel()
 
-	res := make(chan error)
-	go func() {
-		for u.Data.Ready == false {
-			time.Sleep(1 * time.Second)
-			if err := u.updateRoomData(); err != nil {
-				res <- err
-				break
-			}
-			if u.Data.Room.Shutdown {
-				res <- errors.New("Meeting has been shutdown")
-				break
-			}
-		}
-		close(res)
-	}()
-	for {
+	for !u.Data.Ready {
 		select {
-		case err := <-res:
-			return err
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+		if err := u.updateRoomData(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if u.Data.Room.Shutdown {
+			return errors.New("Meeting has been shutdown")
 		}
 	}
+	return nil
 }
 
-func (u *UserService) updateRoomData() error {
+func (u *UserService) updateRoomData(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.get_room", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey
-	req, err := u.client.NewRequest(http.MethodGet, path, url.Values{})
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodGet, path, url.Values{})
 	if err != nil {
 		return err
 	}
@@ -112,11 +113,17 @@ func (u *UserService) updateRoomData() error {
 
 // Chat sends a chat message.
 func (u *UserService) Chat(content string) error {
+	return u.ChatContext(context.Background(), content)
+}
+
+// ChatContext is like Chat but uses the given context for the request.
+func (u *UserService) ChatContext(ctx context.Context, content string) error {
+	ctx = withOperation(ctx, "user.chat", u.Data.Room.ID)
 	data := url.Values{}
 	data.Set("type", "chat")
 	data.Set("content", content)
 	path := "/rooms/" + u.Data.AccessKey + "/messages"
-	req, err := u.client.NewRequest(http.MethodPost, path, data)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, data)
 	if err != nil {
 		return err
 	}
@@ -130,11 +137,17 @@ func (u *UserService) Chat(content string) error {
 // SendCustomMessage sends a custom message to all participants
 // in this meeting.
 func (u *UserService) SendCustomMessage(content string) error {
+	return u.SendCustomMessageContext(context.Background(), content)
+}
+
+// SendCustomMessageContext is like SendCustomMessage but uses the given context for the request.
+func (u *UserService) SendCustomMessageContext(ctx context.Context, content string) error {
+	ctx = withOperation(ctx, "user.send_custom_message", u.Data.Room.ID)
 	data := url.Values{}
 	data.Set("type", "custom")
 	data.Set("content", content)
 	path := "/rooms/" + u.Data.AccessKey + "/messages"
-	req, err := u.client.NewRequest(http.MethodPost, path, data)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, data)
 	if err != nil {
 		return err
 	}
@@ -147,8 +160,14 @@ func (u *UserService) SendCustomMessage(content string) error {
 
 // StartRecording starts a recording.
 func (u *UserService) StartRecording() error {
+	return u.StartRecordingContext(context.Background())
+}
+
+// StartRecordingContext is like StartRecording but uses the given context for the request.
+func (u *UserService) StartRecordingContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.start_recording", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/recording"
-	req, err := u.client.NewRequest(http.MethodPost, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
 	if err != nil {
 		return err
 	}
@@ -161,8 +180,14 @@ func (u *UserService) StartRecording() error {
 
 // StopRecording stops a recording.
 func (u *UserService) StopRecording() error {
+	return u.StopRecordingContext(context.Background())
+}
+
+// StopRecordingContext is like StopRecording but uses the given context for the request.
+func (u *UserService) StopRecordingContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.stop_recording", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/recording"
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -176,10 +201,16 @@ func (u *UserService) StopRecording() error {
 // StartBroadcast starts a broadcast to the given stream url given by a
 // streaming service like YouTube, Vimeo, and others.
 func (u *UserService) StartBroadcast(streamURL string) error {
+	return u.StartBroadcastContext(context.Background(), streamURL)
+}
+
+// StartBroadcastContext is like StartBroadcast but uses the given context for the request.
+func (u *UserService) StartBroadcastContext(ctx context.Context, streamURL string) error {
+	ctx = withOperation(ctx, "user.start_broadcast", u.Data.Room.ID)
 	data := url.Values{}
 	data.Set("stream_url", streamURL)
 	path := "/rooms/" + u.Data.AccessKey + "/broadcasts"
-	req, err := u.client.NewRequest(http.MethodPost, path, data)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, data)
 	if err != nil {
 		return err
 	}
@@ -192,8 +223,14 @@ func (u *UserService) StartBroadcast(streamURL string) error {
 
 // StopBroadcast stops a broadcast.
 func (u *UserService) StopBroadcast() error {
+	return u.StopBroadcastContext(context.Background())
+}
+
+// StopBroadcastContext is like StopBroadcast but uses the given context for the request.
+func (u *UserService) StopBroadcastContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.stop_broadcast", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/broadcasts"
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -296,6 +333,13 @@ type SetLayoutOptions struct {
 // actively by voice detection. The flag showNames show or hides participant
 // name overlays.
 func (u *UserService) SetLayout(layout Layout, options *SetLayoutOptions) error {
+	return u.SetLayoutContext(context.Background(), layout, options)
+}
+
+// SetLayoutContext is like SetLayout but uses the given context for the request.
+func (u *UserService) SetLayoutContext(ctx context.Context, layout Layout,
+	options *SetLayoutOptions) error {
+	ctx = withOperation(ctx, "user.set_layout", u.Data.Room.ID)
 	data := url.Values{}
 	if layout == "custom" {
 		data.Set("layout", "custom")
@@ -334,7 +378,7 @@ func (u *UserService) SetLayout(layout Layout, options *SetLayoutOptions) error
 	}
 
 	path := "/rooms/" + u.Data.AccessKey + "/layout"
-	req, err := u.client.NewRequest(http.MethodPost, path, data)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, data)
 	if err != nil {
 		return err
 	}
@@ -355,6 +399,13 @@ type LayerOptions struct {
 // an image file. The z-index should be set using the constants Foreground or
 // Background.
 func (u *UserService) SetLayer(imgURL string, zIndex int, options *LayerOptions) error {
+	return u.SetLayerContext(context.Background(), imgURL, zIndex, options)
+}
+
+// SetLayerContext is like SetLayer but uses the given context for the request.
+func (u *UserService) SetLayerContext(ctx context.Context, imgURL string, zIndex int,
+	options *LayerOptions) error {
+	ctx = withOperation(ctx, "user.set_layer", u.Data.Room.ID)
 	data := url.Values{}
 	data.Set("url", imgURL)
 	if zIndex == 1 {
@@ -366,7 +417,7 @@ func (u *UserService) SetLayer(imgURL string, zIndex int, options *LayerOptions)
 		data.Set("id", options.ID)
 	}
 	path := "/rooms/" + u.Data.AccessKey + "/layers"
-	req, err := u.client.NewRequest(http.MethodPost, path, data)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, data)
 	if err != nil {
 		return err
 	}
@@ -382,6 +433,13 @@ func (u *UserService) SetLayer(imgURL string, zIndex int, options *LayerOptions)
 // Background.
 func (u *UserService) SetLayerImage(imgData []byte, imageType ImageType, zIndex int,
 	options *LayerOptions) error {
+	return u.SetLayerImageContext(context.Background(), imgData, imageType, zIndex, options)
+}
+
+// SetLayerImageContext is like SetLayerImage but uses the given context for the request.
+func (u *UserService) SetLayerImageContext(ctx context.Context, imgData []byte,
+	imageType ImageType, zIndex int, options *LayerOptions) error {
+	ctx = withOperation(ctx, "user.set_layer_image", u.Data.Room.ID)
 	body := &bytes.Buffer{}
 	// Create a multipart writer
 	writer := multipart.NewWriter(body)
@@ -411,7 +469,8 @@ func (u *UserService) SetLayerImage(imgData []byte, imageType ImageType, zIndex
 	}
 	writer.Close()
 	path := "/rooms/" + u.Data.AccessKey + "/layers"
-	req, err := u.client.NewPlainRequest(http.MethodPost, path, body, writer.FormDataContentType())
+	req, err := u.client.NewPlainRequestWithContext(ctx, http.MethodPost, path, body,
+		writer.FormDataContentType())
 	if err != nil {
 		return err
 	}
@@ -425,13 +484,19 @@ func (u *UserService) SetLayerImage(imgData []byte, imageType ImageType, zIndex
 // ClearLayer clears a layer given by the z-index that should be set using
 // the constants Foreground or Background.
 func (u *UserService) ClearLayer(zIndex int) error {
+	return u.ClearLayerContext(context.Background(), zIndex)
+}
+
+// ClearLayerContext is like ClearLayer but uses the given context for the request.
+func (u *UserService) ClearLayerContext(ctx context.Context, zIndex int) error {
+	ctx = withOperation(ctx, "user.clear_layer", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/layers/"
 	if zIndex == 1 {
 		path += "1"
 	} else {
 		path += "-1"
 	}
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -463,6 +528,13 @@ type PlaybackOptions struct {
 // is going to be replaced while the playback is shown. If replacedUserID is left empty
 // the playback is shown as a separate participant of the meeting.
 func (u *UserService) StartPlayback(playbackURL string, options *PlaybackOptions) error {
+	return u.StartPlaybackContext(context.Background(), playbackURL, options)
+}
+
+// StartPlaybackContext is like StartPlayback but uses the given context for the request.
+func (u *UserService) StartPlaybackContext(ctx context.Context, playbackURL string,
+	options *PlaybackOptions) error {
+	ctx = withOperation(ctx, "user.start_playback", u.Data.Room.ID)
 	data := url.Values{}
 	data.Set("playback[url]", playbackURL)
 	if options != nil {
@@ -483,7 +555,7 @@ func (u *UserService) StartPlayback(playbackURL string, options *PlaybackOptions
 		}
 	}
 	path := "/rooms/" + u.Data.AccessKey + "/playbacks"
-	req, err := u.client.NewRequest(http.MethodPost, path, data)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, data)
 	if err != nil {
 		return err
 	}
@@ -496,8 +568,14 @@ func (u *UserService) StartPlayback(playbackURL string, options *PlaybackOptions
 
 // StopPlayback Stops a playback by its playID.
 func (u *UserService) StopPlayback(playID string) error {
+	return u.StopPlaybackContext(context.Background(), playID)
+}
+
+// StopPlaybackContext is like StopPlayback but uses the given context for the request.
+func (u *UserService) StopPlaybackContext(ctx context.Context, playID string) error {
+	ctx = withOperation(ctx, "user.stop_playback", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/playbacks/" + playID
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -510,8 +588,14 @@ func (u *UserService) StopPlayback(playID string) error {
 
 // StopMeeting stops a meeting for all participants.
 func (u *UserService) StopMeeting() error {
+	return u.StopMeetingContext(context.Background())
+}
+
+// StopMeetingContext is like StopMeeting but uses the given context for the request.
+func (u *UserService) StopMeetingContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.stop_meeting", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey
-	req, err := u.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -524,8 +608,14 @@ func (u *UserService) StopMeeting() error {
 
 // CreateSnapshot creates a new snapshot of the current meeting
 func (u *UserService) CreateSnapshot() error {
+	return u.CreateSnapshotContext(context.Background())
+}
+
+// CreateSnapshotContext is like CreateSnapshot but uses the given context for the request.
+func (u *UserService) CreateSnapshotContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.create_snapshot", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/snapshot"
-	req, err := u.client.NewRequest(http.MethodPost, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
 	if err != nil {
 		return err
 	}
@@ -538,8 +628,14 @@ func (u *UserService) CreateSnapshot() error {
 
 // GetSnapshot Retrieves a snapshot from a running meeting.
 func (u *UserService) GetSnapshot(snapshotID string) (*Snapshot, error) {
+	return u.GetSnapshotContext(context.Background(), snapshotID)
+}
+
+// GetSnapshotContext is like GetSnapshot but uses the given context for the request.
+func (u *UserService) GetSnapshotContext(ctx context.Context, snapshotID string) (*Snapshot, error) {
+	ctx = withOperation(ctx, "user.get_snapshot", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/snapshots/" + snapshotID
-	req, err := u.client.NewRequest(http.MethodGet, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -553,8 +649,14 @@ func (u *UserService) GetSnapshot(snapshotID string) (*Snapshot, error) {
 
 // LockMeeting locks a meeting, dissalowing new participants from joining
 func (u *UserService) LockMeeting() error {
+	return u.LockMeetingContext(context.Background())
+}
+
+// LockMeetingContext is like LockMeeting but uses the given context for the request.
+func (u *UserService) LockMeetingContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "user.lock_meeting", u.Data.Room.ID)
 	path := "/rooms/" + u.Data.AccessKey + "/lock"
-	req, err := u.client.NewRequest(http.MethodPost, path, nil)
+	req, err := u.client.NewRequestWithContext(ctx, http.MethodPost, path, nil)
 	if err != nil {
 		return err
 	}
@@ -1,10 +1,13 @@
 package eyeson
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func TestUserService_Chat(t *testing.T) {
@@ -270,3 +273,26 @@ func TestUserService_StopMeeting(t *testing.T) {
 		t.Errorf("UserService could not stop a meeting, got %v", err)
 	}
 }
+
+func TestUserService_WaitReadyContext(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_key":"token","ready":false}`)
+	})
+	user, err := client.Rooms.Join("", "mike@eyeson.team", nil)
+	if err != nil {
+		t.Fatalf("RoomsService Join not successfull, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err = user.WaitReadyContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected WaitReadyContext to return on cancel, took %v", elapsed)
+	}
+}
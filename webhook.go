@@ -8,6 +8,8 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,17 +17,165 @@ const WEBHOOK_ROOM string = "room_update"
 const WEBHOOK_RECORDING string = "recording_update"
 const WEBHOOK_SNAPSHOT string = "snapshot_update"
 
+// WebhookType is a type of webhook to register for.
+type WebhookType string
+
+// Webhook types.
+const (
+	WebhookRoomUpdate      WebhookType = "room_update"
+	WebhookRecordingUpdate WebhookType = "recording_update"
+	WebhookSnapshotUpdate  WebhookType = "snapshot_update"
+)
+
+// WebhookTypes is a set of webhook types. It decodes from a JSON array as
+// well as from a comma-separated string.
+type WebhookTypes []WebhookType
+
+// ParseWebhookTypes splits a comma-separated list of webhook types.
+func ParseWebhookTypes(types string) WebhookTypes {
+	parsed := WebhookTypes{}
+	for _, t := range strings.Split(types, ",") {
+		if t = strings.TrimSpace(t); t != "" && !parsed.Contains(WebhookType(t)) {
+			parsed = append(parsed, WebhookType(t))
+		}
+	}
+	return parsed
+}
+
+// String provides the comma-separated list of types as expected by the API.
+func (t WebhookTypes) String() string {
+	types := make([]string, len(t))
+	for i, v := range t {
+		types[i] = string(v)
+	}
+	return strings.Join(types, ",")
+}
+
+// Contains reports whether the set contains the type.
+func (t WebhookTypes) Contains(webhookType WebhookType) bool {
+	for _, v := range t {
+		if v == webhookType {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal reports whether both sets contain the same types in any order.
+func (t WebhookTypes) Equal(other WebhookTypes) bool {
+	for _, v := range t {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	for _, v := range other {
+		if !t.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON decodes an array or a comma-separated string of types.
+func (t *WebhookTypes) UnmarshalJSON(data []byte) error {
+	var list string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*t = ParseWebhookTypes(list)
+		return nil
+	}
+	var types []WebhookType
+	if err := json.Unmarshal(data, &types); err != nil {
+		return err
+	}
+	*t = types
+	return nil
+}
+
 // WebhookDetails provide configuration details.
 type WebhookDetails struct {
-	Id                string    `json:"id"`
-	Url               string    `json:"url"`
-	Types             []string  `json:"types"`
+	Id  string `json:"id"`
+	Url string `json:"url"`
+	// Types decodes from a JSON array as well as from the comma-separated
+	// string sent by the API, see TypeSet.
+	Types []string `json:"types"`
+	// LastRequestSentAt is the time of the last delivery, zero if none.
 	LastRequestSentAt time.Time `json:"last_request_sent_at"`
-	LastResponseCode  string    `json:"last_response_code"`
+	// LastResponseCode is the status code the endpoint responded to the last
+	// delivery with, empty if none.
+	LastResponseCode string `json:"last_response_code"`
 }
 
-// Webhook holds available attributes to a room.
+// TypeSet provides the types as set.
+func (d *WebhookDetails) TypeSet() WebhookTypes {
+	types := make(WebhookTypes, len(d.Types))
+	for i, t := range d.Types {
+		types[i] = WebhookType(t)
+	}
+	return types
+}
+
+// UnmarshalJSON decodes the details, accepting types as string, missing
+// delivery times and numeric response codes.
+func (d *WebhookDetails) UnmarshalJSON(data []byte) error {
+	type details WebhookDetails
+	var raw struct {
+		details
+		Types             WebhookTypes    `json:"types"`
+		LastRequestSentAt *string         `json:"last_request_sent_at"`
+		LastResponseCode  json.RawMessage `json:"last_response_code"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*d = WebhookDetails(raw.details)
+	if raw.Types != nil {
+		d.Types = make([]string, len(raw.Types))
+		for i, t := range raw.Types {
+			d.Types[i] = string(t)
+		}
+	}
+	if raw.LastRequestSentAt != nil && *raw.LastRequestSentAt != "" {
+		sentAt, err := time.Parse(time.RFC3339Nano, *raw.LastRequestSentAt)
+		if err != nil {
+			return err
+		}
+		d.LastRequestSentAt = sentAt
+	}
+	if len(raw.LastResponseCode) > 0 && string(raw.LastResponseCode) != "null" {
+		var code string
+		if err := json.Unmarshal(raw.LastResponseCode, &code); err != nil {
+			code = string(raw.LastResponseCode)
+		}
+		d.LastResponseCode = code
+	}
+	return nil
+}
+
+// ResponseCode provides the last response code as number, zero if no
+// delivery has been made yet.
+func (d *WebhookDetails) ResponseCode() int {
+	code, err := strconv.Atoi(d.LastResponseCode)
+	if err != nil {
+		return 0
+	}
+	return code
+}
+
+// Healthy reports whether the last delivery has been accepted by the
+// endpoint with a 2xx status code. Webhooks without delivery are healthy.
+func (d *WebhookDetails) Healthy() bool {
+	if d.LastResponseCode == "" {
+		return true
+	}
+	code := d.ResponseCode()
+	return code >= 200 && code < 300
+}
+
+// Webhook holds available attributes to a room. ParseWebhookEvent provides
+// the complete, typed payloads.
 type Webhook struct {
+	// Timestamp is the unix time the webhook has been sent.
+	Timestamp int64  `json:"timestamp"`
 	Type      string `json:"type"`
 	Recording struct {
 		Id        string `json:"id"`
@@ -34,15 +184,23 @@ type Webhook struct {
 		Links     struct {
 			Download string `json:"download"`
 		} `json:"links"`
+		User EventUser `json:"user"`
 		Room struct {
-			Id string `json:"id"`
-		}
+			Id         string    `json:"id"`
+			Name       string    `json:"name"`
+			Ready      bool      `json:"ready"`
+			StartedAt  time.Time `json:"started_at"`
+			Shutdown   bool      `json:"shutdown"`
+			GuestToken string    `json:"guest_token"`
+		} `json:"room"`
 	} `json:"recording,omitempty"`
 	Room struct {
-		Id        string    `json:"id"`
-		Name      string    `json:"name"`
-		StartedAt time.Time `json:"started_at"`
-		Shutdown  bool      `json:"shutdown"`
+		Id         string    `json:"id"`
+		Name       string    `json:"name"`
+		Ready      bool      `json:"ready"`
+		StartedAt  time.Time `json:"started_at"`
+		Shutdown   bool      `json:"shutdown"`
+		GuestToken string    `json:"guest_token"`
 	} `json:"room"`
 	Snapshot struct {
 		Id        string    `json:"id"`
@@ -51,25 +209,75 @@ type Webhook struct {
 		Links     struct {
 			Download string `json:"download"`
 		} `json:"links"`
-		Room struct {
-			Id string `json:"id"`
-		}
+		Creator EventUser `json:"creator"`
+		Room    struct {
+			Id         string    `json:"id"`
+			Name       string    `json:"name"`
+			Ready      bool      `json:"ready"`
+			StartedAt  time.Time `json:"started_at"`
+			Shutdown   bool      `json:"shutdown"`
+			GuestToken string    `json:"guest_token"`
+		} `json:"room"`
 	} `json:"snapshot,omitempty"`
 }
 
+// Time provides the time the webhook has been sent.
+func (w *Webhook) Time() time.Time {
+	return time.Unix(w.Timestamp, 0)
+}
+
+// CheckFreshness returns ErrWebhookStale if the webhook has been sent more than
+// maxAge ago or in the future by more than maxAge.
+func (w *Webhook) CheckFreshness(maxAge time.Duration) error {
+	age := time.Since(w.Time())
+	if age > maxAge || age < -maxAge {
+		return ErrWebhookStale
+	}
+	return nil
+}
+
+// ErrWebhookStale is returned for webhooks outside of the freshness window.
+var ErrWebhookStale = errors.New("Webhook timestamp outside of freshness window")
+
+// ErrWebhookSignature is returned if the signature of a webhook does not
+// match its payload.
+var ErrWebhookSignature = errors.New("Webhook signature does not match")
+
 func NewWebhook(apiKey string, r *http.Request) (*Webhook, error) {
 	var webhook Webhook
 	raw, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		return nil, err
 	}
-	h := hmac.New(sha256.New, []byte(apiKey))
-	h.Write(raw)
-	if hex.EncodeToString(h.Sum(nil)) != r.Header.Get("X-Eyeson-Signature") {
-		return nil, errors.New("Webhook signature does not match")
+	if err = verifyWebhook(apiKey, raw, r.Header.Get("X-Eyeson-Signature")); err != nil {
+		return nil, err
 	}
 	if err = json.Unmarshal(raw, &webhook); err != nil {
 		return nil, err
 	}
 	return &webhook, nil
 }
+
+// verifyWebhook checks the signature of a webhook payload.
+func verifyWebhook(apiKey string, payload []byte, signature string) error {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrWebhookSignature
+	}
+	if !hmac.Equal(webhookMAC(apiKey, payload), expected) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+func webhookMAC(apiKey string, payload []byte) []byte {
+	h := hmac.New(sha256.New, []byte(apiKey))
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// SignWebhook provides the signature of a webhook payload as sent by the API
+// in the X-Eyeson-Signature header.
+func SignWebhook(apiKey string, payload []byte) string {
+	return hex.EncodeToString(webhookMAC(apiKey, payload))
+}
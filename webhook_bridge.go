@@ -0,0 +1,154 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errBridgeClosed is returned for webhooks received after Close.
+var errBridgeClosed = errors.New("Webhook bridge closed")
+
+// ObserverEvent converts the webhook into the event the observer sends for the
+// same change: a *RoomUpdate, *RecordingUpdate or *SnapshotUpdate. Other
+// webhook types provide nil.
+func (w *Webhook) ObserverEvent() EventInterface {
+	switch w.Type {
+	case WEBHOOK_ROOM:
+		return &RoomUpdate{
+			EventBase: EventBase{Type: "room_update"},
+			Content: EventRoom{ID: w.Room.Id, Name: w.Room.Name, Ready: w.Room.Ready,
+				StartedAt: w.Room.StartedAt, Shutdown: w.Room.Shutdown, GuestToken: w.Room.GuestToken},
+		}
+	case WEBHOOK_RECORDING:
+		duration := w.Recording.Duration
+		return &RecordingUpdate{
+			EventBase: EventBase{Type: "recording_update"},
+			Recording: Recording{ID: w.Recording.Id, CreatedAt: w.Recording.CreatedAt,
+				Duration: &duration, Links: webhookLinks(w.Recording.Links.Download),
+				User: w.Recording.User,
+				Room: EventRoom{ID: w.Recording.Room.Id, Name: w.Recording.Room.Name,
+					Ready: w.Recording.Room.Ready, StartedAt: w.Recording.Room.StartedAt,
+					Shutdown: w.Recording.Room.Shutdown, GuestToken: w.Recording.Room.GuestToken}},
+		}
+	case WEBHOOK_SNAPSHOT:
+		return &SnapshotUpdate{
+			EventBase: EventBase{Type: "snapshot_update"},
+			Snapshots: []Snapshot{{ID: w.Snapshot.Id, Name: w.Snapshot.Name,
+				CreatedAt: w.Snapshot.CreatedAt, Links: webhookLinks(w.Snapshot.Links.Download),
+				Creator: w.Snapshot.Creator,
+				Room: EventRoom{ID: w.Snapshot.Room.Id, Name: w.Snapshot.Room.Name,
+					Ready: w.Snapshot.Room.Ready, StartedAt: w.Snapshot.Room.StartedAt,
+					Shutdown: w.Snapshot.Room.Shutdown, GuestToken: w.Snapshot.Room.GuestToken}}},
+		}
+	}
+	return nil
+}
+
+func webhookLinks(download string) Links {
+	if download == "" {
+		return Links{}
+	}
+	return Links{Download: &download}
+}
+
+// WebhookObserverEvent converts a typed webhook into the event the observer
+// sends for the same change, like Webhook.ObserverEvent. Other webhook types
+// provide nil.
+func WebhookObserverEvent(event WebhookEvent) EventInterface {
+	switch webhook := event.(type) {
+	case *RoomWebhook:
+		return &RoomUpdate{EventBase: EventBase{Type: "room_update"}, Content: webhook.Room}
+	case *RecordingWebhook:
+		return &RecordingUpdate{EventBase: EventBase{Type: "recording_update"},
+			Recording: webhook.Recording}
+	case *SnapshotWebhook:
+		return &SnapshotUpdate{EventBase: EventBase{Type: "snapshot_update"},
+			Snapshots: []Snapshot{webhook.Snapshot}}
+	}
+	return nil
+}
+
+// WebhookBridge provides received webhooks as observer events on a channel,
+// so the same consumers, like an EventRouter or RoomState, serve both
+// transports.
+//
+//	bridge := eyeson.NewWebhookBridge(16)
+//	handler := eyeson.NewWebhookHandler(apiKey)
+//	bridge.Register(handler)
+//	go router.Run(ctx, bridge.Events())
+type WebhookBridge struct {
+	events    chan EventInterface
+	done      chan struct{}
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+}
+
+// NewWebhookBridge creates a bridge whose events channel buffers the given
+// number of events.
+func NewWebhookBridge(buffer int) *WebhookBridge {
+	if buffer < 0 {
+		buffer = 0
+	}
+	return &WebhookBridge{
+		events: make(chan EventInterface, buffer),
+		done:   make(chan struct{}),
+	}
+}
+
+// Events provides the converted webhooks. The channel is closed by Close.
+func (b *WebhookBridge) Events() <-chan EventInterface {
+	return b.events
+}
+
+// Register sets the bridge as callback for room, recording and snapshot
+// webhooks of the handler.
+func (b *WebhookBridge) Register(h *WebhookHandler) {
+	h.OnRoomUpdate(b.Handle)
+	h.OnRecordingUpdate(b.Handle)
+	h.OnSnapshotUpdate(b.Handle)
+}
+
+// Handle implements WebhookFunc. It waits until the event is taken from the
+// channel or buffered, and returns the context error otherwise, so the
+// webhook is delivered again. Webhooks without observer event are ignored.
+func (b *WebhookBridge) Handle(ctx context.Context, webhook *Webhook) error {
+	return b.publish(ctx, webhook.ObserverEvent())
+}
+
+// Publish is like Handle for a typed webhook, keeping its complete payload.
+func (b *WebhookBridge) Publish(ctx context.Context, event WebhookEvent) error {
+	return b.publish(ctx, WebhookObserverEvent(event))
+}
+
+func (b *WebhookBridge) publish(ctx context.Context, event EventInterface) error {
+	if event == nil {
+		return nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return errBridgeClosed
+	}
+	select {
+	case b.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.done:
+		return errBridgeClosed
+	}
+}
+
+// Close closes the events channel. Waiting and later webhooks fail, so the
+// API delivers them again.
+func (b *WebhookBridge) Close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		b.mu.Lock()
+		b.closed = true
+		close(b.events)
+		b.mu.Unlock()
+	})
+}
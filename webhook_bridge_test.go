@@ -0,0 +1,136 @@
+package eyeson
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWebhook_observerEvent(t *testing.T) {
+	for _, fixture := range []string{"room", "recording", "snapshot"} {
+		payload, err := os.ReadFile("./fixtures/webhook_" + fixture + "_update.json")
+		if err != nil {
+			t.Fatalf("Failed to read fixture file: %v", err)
+		}
+		var webhook Webhook
+		if err = json.Unmarshal(payload, &webhook); err != nil {
+			t.Fatalf("Failed to decode sample: %v", err)
+		}
+		typed, err := ParseWebhookEvent(payload)
+		if err != nil {
+			t.Fatalf("Failed to parse sample: %v", err)
+		}
+
+		for _, event := range []EventInterface{webhook.ObserverEvent(), WebhookObserverEvent(typed)} {
+			if event == nil || event.GetType() != webhook.Type {
+				t.Fatalf("Expected %s event, got %#v", webhook.Type, event)
+			}
+			switch ev := event.(type) {
+			case *RoomUpdate:
+				if ev.Content.ID != "demo" || !ev.Content.Ready || ev.Content.GuestToken == "" {
+					t.Errorf("Unexpected room %+v", ev.Content)
+				}
+			case *RecordingUpdate:
+				if ev.Recording.Duration == nil || *ev.Recording.Duration != 2 ||
+					ev.Recording.Links.Download == nil || ev.Recording.Room.ID != "demo" ||
+					ev.Recording.Room.Name != "John" || ev.Recording.User.Name != "chl" {
+					t.Errorf("Unexpected recording %+v", ev.Recording)
+				}
+			case *SnapshotUpdate:
+				if len(ev.Snapshots) != 1 || ev.Snapshots[0].Name != "2345" ||
+					ev.Snapshots[0].Room.ID != "demo" || ev.Snapshots[0].Room.Name != "Test-Room" ||
+					ev.Snapshots[0].Creator.Name != "user" {
+					t.Errorf("Unexpected snapshots %+v", ev.Snapshots)
+				}
+			default:
+				t.Errorf("Unexpected event %T", event)
+			}
+		}
+	}
+
+	if event := (&Webhook{Type: "unknown"}).ObserverEvent(); event != nil {
+		t.Errorf("Expected no event for unknown type, got %v", event)
+	}
+	if event := WebhookObserverEvent(&RawWebhook{}); event != nil {
+		t.Errorf("Expected no event for raw webhook, got %v", event)
+	}
+}
+
+func TestWebhookBridge_register(t *testing.T) {
+	bridge := NewWebhookBridge(1)
+	defer bridge.Close()
+	handler := NewWebhookHandler("secret")
+	bridge.Register(handler)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_recording_update.json"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %v", rec.Code)
+	}
+	ev, ok := (<-bridge.Events()).(*RecordingUpdate)
+	if !ok || ev.Recording.User.Name != "chl" || ev.Recording.Room.Name != "John" ||
+		!ev.Recording.Room.Ready {
+		t.Errorf("Expected complete recording update, got %+v", ev)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_snapshot_update.json"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %v", rec.Code)
+	}
+	snapshot, ok := (<-bridge.Events()).(*SnapshotUpdate)
+	if !ok || len(snapshot.Snapshots) != 1 || snapshot.Snapshots[0].Creator.Name != "user" ||
+		snapshot.Snapshots[0].Room.Name != "Test-Room" {
+		t.Errorf("Expected complete snapshot update, got %+v", snapshot)
+	}
+}
+
+func TestWebhookBridge(t *testing.T) {
+	bridge := NewWebhookBridge(1)
+	handler := NewWebhookHandler("secret")
+	bridge.Register(handler)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %v", rec.Code)
+	}
+	if ev, ok := (<-bridge.Events()).(*RoomUpdate); !ok || ev.Content.ID != "demo" {
+		t.Errorf("Expected room update, got %v", ev)
+	}
+
+	// fill the buffer, the next webhook fails when the context ends
+	bridge.Handle(context.Background(), &Webhook{Type: WEBHOOK_SNAPSHOT})
+	rec = httptest.NewRecorder()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := signedWebhookRequest(t, "secret", "./fixtures/webhook_recording_update.json")
+	handler.ServeHTTP(rec, req.WithContext(ctx))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 for blocked bridge, got %v", rec.Code)
+	}
+
+	blocked := make(chan error)
+	go func() {
+		blocked <- bridge.Handle(context.Background(), &Webhook{Type: WEBHOOK_ROOM})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	bridge.Close()
+	if err := <-blocked; err != errBridgeClosed {
+		t.Errorf("Expected waiting webhook to fail on close, got %v", err)
+	}
+	if _, ok := (<-bridge.Events()).(*SnapshotUpdate); !ok {
+		t.Error("Expected buffered snapshot update")
+	}
+	if _, ok := <-bridge.Events(); ok {
+		t.Error("Expected events channel to be closed")
+	}
+	if err := bridge.Publish(context.Background(), &RoomWebhook{}); err != errBridgeClosed {
+		t.Errorf("Expected error after close, got %v", err)
+	}
+	bridge.Close()
+}
@@ -0,0 +1,81 @@
+package eyeson
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// DefaultWebhookDedupeSize is the number of webhooks remembered by the
+// default dedupe store of a WebhookHandler.
+const DefaultWebhookDedupeSize = 4096
+
+// WebhookDedupeStore remembers processed webhooks, so deliveries repeated by
+// the API are processed once.
+type WebhookDedupeStore interface {
+	// Seen reports whether the key has been marked.
+	Seen(ctx context.Context, key string) (bool, error)
+	// Mark records the key once the webhook has been processed successfully.
+	Mark(ctx context.Context, key string) error
+}
+
+// WebhookKey provides the dedupe key of a webhook payload, the hex encoded
+// SHA-256 hash of the payload.
+func WebhookKey(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryDedupeStore is a WebhookDedupeStore keeping the most recent keys in
+// memory. The least recently seen keys are evicted first.
+type MemoryDedupeStore struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+// NewMemoryDedupeStore creates a store remembering up to size keys.
+func NewMemoryDedupeStore(size int) *MemoryDedupeStore {
+	if size <= 0 {
+		size = DefaultWebhookDedupeSize
+	}
+	return &MemoryDedupeStore{size: size, order: list.New(), keys: map[string]*list.Element{}}
+}
+
+// Seen implements the WebhookDedupeStore interface.
+func (s *MemoryDedupeStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.keys[key]
+	if ok {
+		s.order.MoveToFront(el)
+	}
+	return ok, nil
+}
+
+// Mark implements the WebhookDedupeStore interface.
+func (s *MemoryDedupeStore) Mark(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.keys[key] = s.order.PushFront(key)
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+	return nil
+}
+
+// Len provides the number of remembered keys.
+func (s *MemoryDedupeStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,31 @@
+package eyeson
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryDedupeStore(t *testing.T) {
+	store := NewMemoryDedupeStore(2)
+	ctx := context.Background()
+	if seen, _ := store.Seen(ctx, "a"); seen || store.Len() != 0 {
+		t.Errorf("Expected Seen not to record keys, len %d", store.Len())
+	}
+	for _, key := range []string{"a", "b", "a", "c"} {
+		store.Mark(ctx, key)
+	}
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if seen, _ := store.Seen(ctx, key); seen != want {
+			t.Errorf("Expected seen %v for %s", want, key)
+		}
+	}
+	if store.Len() != 2 {
+		t.Errorf("Expected 2 keys, got %d", store.Len())
+	}
+}
+
+func TestWebhookKey(t *testing.T) {
+	if WebhookKey([]byte("a")) == WebhookKey([]byte("b")) || len(WebhookKey(nil)) != 64 {
+		t.Error("Expected distinct hex encoded SHA-256 keys")
+	}
+}
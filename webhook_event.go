@@ -0,0 +1,105 @@
+package eyeson
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// WebhookEvent interface for all webhook payloads, like EventInterface for
+// observer events.
+type WebhookEvent interface {
+	GetType() string
+	// Time provides the time the webhook has been sent.
+	Time() time.Time
+}
+
+// WebhookBase Base for all webhook payloads. Has the type and timestamp
+// fields.
+type WebhookBase struct {
+	// Timestamp is the unix time the webhook has been sent.
+	Timestamp int64  `json:"timestamp"`
+	Type      string `json:"type"`
+}
+
+// GetType retrieve the type. Implements the WebhookEvent interface.
+func (w *WebhookBase) GetType() string {
+	return w.Type
+}
+
+// Time provides the time the webhook has been sent. Implements the
+// WebhookEvent interface.
+func (w *WebhookBase) Time() time.Time {
+	return time.Unix(w.Timestamp, 0)
+}
+
+// RoomWebhook is sent when a meeting starts or shuts down.
+type RoomWebhook struct {
+	WebhookBase
+	Room EventRoom `json:"room"`
+}
+
+// RecordingWebhook is sent when a recording is available for download.
+type RecordingWebhook struct {
+	WebhookBase
+	Recording Recording `json:"recording"`
+}
+
+// SnapshotWebhook is sent when a snapshot has been taken.
+type SnapshotWebhook struct {
+	WebhookBase
+	Snapshot Snapshot `json:"snapshot"`
+}
+
+// RawWebhook is a webhook of a type without registered struct. It carries
+// the undecoded payload.
+type RawWebhook struct {
+	WebhookBase
+	Raw json.RawMessage `json:"-"`
+}
+
+// MarshalJSON provides the original payload of the webhook.
+func (w *RawWebhook) MarshalJSON() ([]byte, error) {
+	if len(w.Raw) == 0 {
+		return json.Marshal(w.WebhookBase)
+	}
+	return w.Raw, nil
+}
+
+var webhookEventTypes = map[string]func() WebhookEvent{
+	WEBHOOK_ROOM:      func() WebhookEvent { return &RoomWebhook{} },
+	WEBHOOK_RECORDING: func() WebhookEvent { return &RecordingWebhook{} },
+	WEBHOOK_SNAPSHOT:  func() WebhookEvent { return &SnapshotWebhook{} },
+}
+
+// ParseWebhookEvent decodes a webhook payload into its typed struct.
+// Payloads of unknown types are provided as *RawWebhook.
+func ParseWebhookEvent(payload []byte) (WebhookEvent, error) {
+	var base WebhookBase
+	if err := json.Unmarshal(payload, &base); err != nil {
+		return nil, err
+	}
+	factory, ok := webhookEventTypes[base.Type]
+	if !ok {
+		return &RawWebhook{WebhookBase: base, Raw: payload}, nil
+	}
+	event := factory()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewWebhookEvent verifies the signature of a webhook request and decodes
+// its payload like ParseWebhookEvent.
+func NewWebhookEvent(apiKey string, r *http.Request) (WebhookEvent, error) {
+	raw, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = verifyWebhook(apiKey, raw, r.Header.Get("X-Eyeson-Signature")); err != nil {
+		return nil, err
+	}
+	return ParseWebhookEvent(raw)
+}
@@ -0,0 +1,97 @@
+package eyeson
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func parseFixture(t *testing.T, fixture string) WebhookEvent {
+	payload, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+	event, err := ParseWebhookEvent(payload)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", fixture, err)
+	}
+	return event
+}
+
+func TestParseWebhookEvent_room(t *testing.T) {
+	event, ok := parseFixture(t, "./fixtures/webhook_room_update.json").(*RoomWebhook)
+	if !ok {
+		t.Fatalf("Expected *RoomWebhook, got %T", event)
+	}
+	if event.GetType() != WEBHOOK_ROOM || event.Time().Unix() != 1637738200 {
+		t.Errorf("Unexpected base %+v", event.WebhookBase)
+	}
+	if event.Room.ID != "demo" || !event.Room.Ready || event.Room.Shutdown ||
+		event.Room.GuestToken != "********guest********" {
+		t.Errorf("Unexpected room %+v", event.Room)
+	}
+
+	shutdown := parseFixture(t, "./fixtures/webhook_room_shutdown.json").(*RoomWebhook)
+	if !shutdown.Room.Shutdown || shutdown.Room.Ready {
+		t.Errorf("Expected room to be shut down, got %+v", shutdown.Room)
+	}
+}
+
+func TestParseWebhookEvent_recording(t *testing.T) {
+	event, ok := parseFixture(t, "./fixtures/webhook_recording_update.json").(*RecordingWebhook)
+	if !ok {
+		t.Fatalf("Expected *RecordingWebhook, got %T", event)
+	}
+	r := event.Recording
+	if r.Duration == nil || *r.Duration != 2 || r.CreatedAt != 1637747098 {
+		t.Errorf("Unexpected recording %+v", r)
+	}
+	if r.Links.Download == nil || *r.Links.Download != "https://s3.eyeson.com/meetings/*****" {
+		t.Errorf("Expected download link, got %+v", r.Links)
+	}
+	if r.User.Name != "chl" || r.User.Guest || r.Room.ID != "demo" || r.Room.GuestToken == "" {
+		t.Errorf("Expected recording user and room, got %+v %+v", r.User, r.Room)
+	}
+}
+
+func TestParseWebhookEvent_snapshot(t *testing.T) {
+	event, ok := parseFixture(t, "./fixtures/webhook_snapshot_update.json").(*SnapshotWebhook)
+	if !ok {
+		t.Fatalf("Expected *SnapshotWebhook, got %T", event)
+	}
+	s := event.Snapshot
+	if s.Name != "2345" || s.Creator.Name != "user" || s.CreatedAt.Year() != 2022 {
+		t.Errorf("Unexpected snapshot %+v", s)
+	}
+	if s.Room.SIP == nil || s.Room.SIP.AuthorizationUser != "conf_2345" {
+		t.Errorf("Expected sip details of room, got %+v", s.Room.SIP)
+	}
+}
+
+func TestParseWebhookEvent_unknown(t *testing.T) {
+	payload := []byte(`{"timestamp":1,"type":"brand_new","value":1}`)
+	event, err := ParseWebhookEvent(payload)
+	raw, ok := event.(*RawWebhook)
+	if err != nil || !ok || raw.GetType() != "brand_new" || string(raw.Raw) != string(payload) {
+		t.Errorf("Expected raw webhook, got %#v (%v)", event, err)
+	}
+	if _, err = ParseWebhookEvent([]byte(`{"type":"room_update","room":[]}`)); err == nil {
+		t.Error("Expected malformed room update to fail")
+	}
+}
+
+func TestNewWebhookEvent(t *testing.T) {
+	req := signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json")
+	if event, err := NewWebhookEvent("secret", req); err != nil || event.GetType() != WEBHOOK_ROOM {
+		t.Errorf("Expected room webhook, got %v (%v)", event, err)
+	}
+	req = signedWebhookRequest(t, "wrong", "./fixtures/webhook_room_update.json")
+	if _, err := NewWebhookEvent("secret", req); err != ErrWebhookSignature {
+		t.Errorf("Expected ErrWebhookSignature, got %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	if _, err := NewWebhookEvent("secret", req); err != ErrWebhookSignature {
+		t.Errorf("Expected unsigned request to fail, got %v", err)
+	}
+}
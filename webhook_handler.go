@@ -0,0 +1,199 @@
+package eyeson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookBodyLimit is the default maximum size of a webhook payload.
+const DefaultWebhookBodyLimit = 1 << 20
+
+// WebhookFunc handles a received webhook. Returning an error responds with
+// status 500, so the API delivers the webhook again.
+type WebhookFunc func(ctx context.Context, webhook *Webhook) error
+
+// WebhookHandler is an http.Handler receiving webhooks. It verifies their
+// signature and dispatches them by type. It responds with
+//
+//	204 if the webhook has been handled, ignored or is a duplicate,
+//	400 if the payload is invalid or outside of the freshness window,
+//	401 if the signature does not match,
+//	405 for other methods than POST,
+//	409 if the same webhook is still being processed,
+//	413 if the payload exceeds the body limit,
+//	500 if the callback failed.
+//
+// Callbacks have to be registered before the handler serves requests.
+type WebhookHandler struct {
+	apiKey   string
+	handlers map[string]WebhookFunc
+	unknown  WebhookFunc
+
+	mu         sync.Mutex
+	inProgress map[string]bool
+
+	// MaxBodySize limits the size of the payload, DefaultWebhookBodyLimit
+	// by default.
+	MaxBodySize int64
+	// MaxAge rejects webhooks sent longer ago, or in the future by more than
+	// MaxAge, to prevent replays. Zero disables the check.
+	MaxAge time.Duration
+	// Dedupe filters repeated deliveries of the same payload. Webhooks are
+	// marked once processed successfully. It defaults to a MemoryDedupeStore;
+	// nil disables deduplication.
+	Dedupe WebhookDedupeStore
+	// OnError is called for every rejected or failed webhook, e.g. to log it.
+	OnError func(r *http.Request, err error)
+}
+
+// NewWebhookHandler creates a handler for webhooks signed with the given API
+// key.
+func NewWebhookHandler(apiKey string) *WebhookHandler {
+	return &WebhookHandler{
+		apiKey:      apiKey,
+		handlers:    map[string]WebhookFunc{},
+		inProgress:  map[string]bool{},
+		MaxBodySize: DefaultWebhookBodyLimit,
+		Dedupe:      NewMemoryDedupeStore(DefaultWebhookDedupeSize),
+	}
+}
+
+// OnRoomUpdate sets the callback for room_update webhooks.
+func (h *WebhookHandler) OnRoomUpdate(fn WebhookFunc) {
+	h.handlers[WEBHOOK_ROOM] = fn
+}
+
+// OnRecordingUpdate sets the callback for recording_update webhooks.
+func (h *WebhookHandler) OnRecordingUpdate(fn WebhookFunc) {
+	h.handlers[WEBHOOK_RECORDING] = fn
+}
+
+// OnSnapshotUpdate sets the callback for snapshot_update webhooks.
+func (h *WebhookHandler) OnSnapshotUpdate(fn WebhookFunc) {
+	h.handlers[WEBHOOK_SNAPSHOT] = fn
+}
+
+// OnUnknown sets the callback for webhooks without registered callback.
+// Without it, such webhooks are acknowledged and ignored.
+func (h *WebhookHandler) OnUnknown(fn WebhookFunc) {
+	h.unknown = fn
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	raw, status, err := receiveWebhook(w, r, h.apiKey, h.MaxBodySize)
+	if err != nil {
+		h.fail(w, r, status, err)
+		return
+	}
+	var webhook Webhook
+	if err = json.Unmarshal(raw, &webhook); err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if h.MaxAge > 0 {
+		if err = webhook.CheckFreshness(h.MaxAge); err != nil {
+			h.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+	key := WebhookKey(raw)
+	if h.Dedupe != nil {
+		if !h.acquire(key) {
+			// the same webhook is being processed, the API retries later
+			h.fail(w, r, http.StatusConflict, errors.New("Webhook in progress"))
+			return
+		}
+		defer h.release(key)
+		seen, err := h.Dedupe.Seen(r.Context(), key)
+		if err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if seen {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	if err = h.Dispatch(r.Context(), &webhook); err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if h.Dedupe != nil {
+		if err = h.Dedupe.Mark(r.Context(), key); err != nil && h.OnError != nil {
+			// the webhook has been processed, a redelivery is processed again
+			h.OnError(r, err)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// acquire marks the webhook as in progress, reporting false if it already is.
+func (h *WebhookHandler) acquire(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.inProgress[key] {
+		return false
+	}
+	if h.inProgress == nil {
+		h.inProgress = map[string]bool{}
+	}
+	h.inProgress[key] = true
+	return true
+}
+
+func (h *WebhookHandler) release(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.inProgress, key)
+}
+
+// Dispatch passes a webhook to the callback registered for its type, e.g. to
+// process webhooks taken from a WebhookQueue.
+func (h *WebhookHandler) Dispatch(ctx context.Context, webhook *Webhook) error {
+	fn, ok := h.handlers[webhook.Type]
+	if !ok {
+		fn = h.unknown
+	}
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, webhook)
+}
+
+// receiveWebhook reads and verifies the payload of a webhook request. On
+// failure, it provides the status code to respond with.
+func receiveWebhook(w http.ResponseWriter, r *http.Request, apiKey string, limit int64) ([]byte, int, error) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		return nil, http.StatusMethodNotAllowed, errors.New("Method not allowed")
+	}
+	if limit <= 0 {
+		limit = DefaultWebhookBodyLimit
+	}
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, err
+		}
+		return nil, http.StatusBadRequest, err
+	}
+	if err = verifyWebhook(apiKey, raw, r.Header.Get("X-Eyeson-Signature")); err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	return raw, http.StatusOK, nil
+}
+
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
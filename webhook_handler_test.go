@@ -0,0 +1,188 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedWebhookRequest(t *testing.T, apiKey, fixture string) *http.Request {
+	payload, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to read fixture file: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(string(payload)))
+	req.Header.Set("X-Eyeson-Signature", SignWebhook(apiKey, payload))
+	return req
+}
+
+func TestWebhookHandler_dispatch(t *testing.T) {
+	handler := NewWebhookHandler("secret")
+	var got []string
+	handler.OnRoomUpdate(func(ctx context.Context, webhook *Webhook) error {
+		got = append(got, "room:"+webhook.Room.Id)
+		return nil
+	})
+	handler.OnRecordingUpdate(func(ctx context.Context, webhook *Webhook) error {
+		got = append(got, "recording:"+webhook.Recording.Id)
+		return errors.New("storage down")
+	})
+
+	for _, tc := range []struct {
+		fixture string
+		status  int
+	}{
+		{"./fixtures/webhook_room_update.json", http.StatusNoContent},
+		{"./fixtures/webhook_recording_update.json", http.StatusInternalServerError},
+		{"./fixtures/webhook_snapshot_update.json", http.StatusNoContent},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", tc.fixture))
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.fixture, rec.Code)
+		}
+	}
+	if len(got) != 2 || got[0] != "room:demo" || !strings.HasPrefix(got[1], "recording:") {
+		t.Errorf("Unexpected callbacks %v", got)
+	}
+
+	// the snapshot has been delivered before
+	handler.Dedupe = nil
+	var unknown string
+	handler.OnUnknown(func(ctx context.Context, webhook *Webhook) error {
+		unknown = webhook.Type
+		return nil
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_snapshot_update.json"))
+	if rec.Code != http.StatusNoContent || unknown != "snapshot_update" {
+		t.Errorf("Expected unknown fallback, got %d and %q", rec.Code, unknown)
+	}
+}
+
+func TestWebhookHandler_reject(t *testing.T) {
+	handler := NewWebhookHandler("secret")
+	handler.MaxBodySize = 64
+	var errs []error
+	handler.OnError = func(r *http.Request, err error) { errs = append(errs, err) }
+	handler.OnRoomUpdate(func(ctx context.Context, webhook *Webhook) error {
+		t.Error("Expected rejected webhook not to be dispatched")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413, got %d", rec.Code)
+	}
+
+	handler.MaxBodySize = 0
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "wrong", "./fixtures/webhook_room_update.json"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", rec.Code)
+	}
+	if len(errs) != 3 || !errors.Is(errs[2], ErrWebhookSignature) {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("{"))
+	req.Header.Set("X-Eyeson-Signature", SignWebhook("secret", []byte("{")))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandler_replayProtection(t *testing.T) {
+	handler := NewWebhookHandler("secret")
+	calls := 0
+	fail := true
+	handler.OnRoomUpdate(func(ctx context.Context, webhook *Webhook) error {
+		calls++
+		if fail {
+			fail = false
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	for i, status := range []int{http.StatusInternalServerError, http.StatusNoContent, http.StatusNoContent} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+		if rec.Code != status {
+			t.Errorf("Expected status %d for delivery %d, got %d", status, i, rec.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected failed and retried delivery to be processed, got %d calls", calls)
+	}
+
+	handler.MaxAge = time.Minute
+	handler.Dedupe = nil
+	var stale error
+	handler.OnError = func(r *http.Request, err error) { stale = err }
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+	if rec.Code != http.StatusBadRequest || !errors.Is(stale, ErrWebhookStale) {
+		t.Errorf("Expected stale webhook to be rejected, got %d (%v)", rec.Code, stale)
+	}
+}
+
+func TestWebhookHandler_concurrentDuplicate(t *testing.T) {
+	handler := NewWebhookHandler("secret")
+	started := make(chan struct{})
+	finish := make(chan error)
+	calls := 0
+	handler.OnRoomUpdate(func(ctx context.Context, webhook *Webhook) error {
+		calls++
+		if calls == 1 {
+			close(started)
+			return <-finish
+		}
+		return nil
+	})
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(first, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+	}()
+	<-started
+
+	// a retry arriving while the first delivery is processed
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for webhook in progress, got %d", rec.Code)
+	}
+	finish <- errors.New("temporary")
+	<-done
+	if first.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 for failed delivery, got %d", first.Code)
+	}
+
+	for i, status := range []int{http.StatusNoContent, http.StatusNoContent} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+		if rec.Code != status {
+			t.Errorf("Expected status %d for redelivery %d, got %d", status, i, rec.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected the failed webhook to be processed once more, got %d calls", calls)
+	}
+}
@@ -0,0 +1,189 @@
+package eyeson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+// WebhookProcessor receives webhooks and processes them asynchronously. It
+// verifies every webhook, stores it in a queue and acknowledges it at once
+// with status 202, leaving slow work to worker goroutines. Failed webhooks
+// are enqueued again to be retried with exponential backoff, and moved to the
+// dead-letter store after MaxAttempts.
+type WebhookProcessor struct {
+	apiKey  string
+	queue   WebhookQueue
+	process WebhookFunc
+
+	// Workers is the number of worker goroutines, 4 by default.
+	Workers int
+	// MaxAttempts limits the processing attempts per webhook, 5 by default.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, 1s by default.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries, 1m by default.
+	MaxBackoff time.Duration
+	// MaxBodySize limits the size of the payload, DefaultWebhookBodyLimit
+	// by default.
+	MaxBodySize int64
+	// DeadLetters receives webhooks failed MaxAttempts times. Without it,
+	// they are dropped.
+	DeadLetters DeadLetterStore
+	// OnError is called for rejected requests and failed processing
+	// attempts, e.g. to log them. The queued webhook is nil for rejected
+	// requests.
+	OnError func(item *QueuedWebhook, err error)
+}
+
+// NewWebhookProcessor creates a processor for webhooks signed with the given
+// API key, passing them to process, e.g. WebhookHandler.Dispatch.
+func NewWebhookProcessor(apiKey string, queue WebhookQueue, process WebhookFunc) *WebhookProcessor {
+	return &WebhookProcessor{
+		apiKey:      apiKey,
+		queue:       queue,
+		process:     process,
+		Workers:     4,
+		MaxAttempts: 5,
+		MinBackoff:  time.Second,
+		MaxBackoff:  time.Minute,
+		MaxBodySize: DefaultWebhookBodyLimit,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface. It responds with 202 once
+// the webhook is queued, and with 503 if it cannot be queued.
+func (p *WebhookProcessor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	raw, status, err := receiveWebhook(w, r, p.apiKey, p.MaxBodySize)
+	if err == nil && !json.Valid(raw) {
+		status, err = http.StatusBadRequest, fmt.Errorf("Invalid webhook payload")
+	}
+	if err != nil {
+		p.reject(w, status, err)
+		return
+	}
+	id, err := newMessageID()
+	if err != nil {
+		p.reject(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	item := &QueuedWebhook{
+		ID:         fmt.Sprintf("%020d-%s", time.Now().UnixNano(), id),
+		Payload:    raw,
+		ReceivedAt: time.Now(),
+	}
+	if err = p.queue.Enqueue(r.Context(), item); err != nil {
+		p.reject(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (p *WebhookProcessor) reject(w http.ResponseWriter, status int, err error) {
+	p.reportError(nil, err)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// Run processes queued webhooks until the context is done and waits for the
+// workers to finish. Webhooks interrupted by the context are not
+// acknowledged, so durable queues provide them again after a restart.
+func (p *WebhookProcessor) Run(ctx context.Context) error {
+	workers := p.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item, err := p.queue.Dequeue(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						p.reportError(nil, err)
+					}
+					return
+				}
+				p.handle(ctx, item)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// handle makes a single attempt to process a webhook. Failed webhooks are
+// enqueued again with a delay, so workers do not wait for their retries.
+func (p *WebhookProcessor) handle(ctx context.Context, item *QueuedWebhook) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if item.Attempts >= maxAttempts {
+		// dead-lettering failed before
+		p.deadLetter(ctx, item)
+		return
+	}
+
+	var webhook Webhook
+	err := json.Unmarshal(item.Payload, &webhook)
+	if err == nil {
+		err = p.process(ctx, &webhook)
+	}
+	if err == nil {
+		if err = p.queue.Ack(ctx, item.ID); err != nil {
+			p.reportError(item, err)
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	item.Attempts++
+	item.LastError = err.Error()
+	p.reportError(item, err)
+	if item.Attempts >= maxAttempts {
+		p.deadLetter(ctx, item)
+		return
+	}
+	b := &backoff.Backoff{Min: p.MinBackoff, Max: p.MaxBackoff, Factor: 2, Jitter: true}
+	p.retry(ctx, item, b.ForAttempt(float64(item.Attempts-1)))
+}
+
+// retry enqueues the webhook again, to be processed after the delay.
+func (p *WebhookProcessor) retry(ctx context.Context, item *QueuedWebhook, delay time.Duration) {
+	item.NotBefore = time.Now().Add(delay)
+	if err := p.queue.Enqueue(ctx, item); err != nil {
+		p.reportError(item, err)
+	}
+}
+
+func (p *WebhookProcessor) deadLetter(ctx context.Context, item *QueuedWebhook) {
+	if p.DeadLetters != nil {
+		if err := p.DeadLetters.Put(ctx, item); err != nil {
+			// keep the webhook queued and try again later
+			p.reportError(item, err)
+			delay := p.MaxBackoff
+			if delay <= 0 {
+				delay = time.Minute
+			}
+			p.retry(ctx, item, delay)
+			return
+		}
+	}
+	if err := p.queue.Ack(ctx, item.ID); err != nil {
+		p.reportError(item, err)
+	}
+}
+
+func (p *WebhookProcessor) reportError(item *QueuedWebhook, err error) {
+	if p.OnError != nil {
+		p.OnError(item, err)
+	}
+}
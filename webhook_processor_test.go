@@ -0,0 +1,168 @@
+package eyeson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookProcessor(t *testing.T) {
+	queue := NewMemoryWebhookQueue()
+	deadLetters := NewMemoryDeadLetterStore()
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	done := make(chan string, 2)
+	processor := NewWebhookProcessor("secret", queue, func(ctx context.Context, webhook *Webhook) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[webhook.Type]++
+		switch {
+		case webhook.Type == WEBHOOK_ROOM && attempts[webhook.Type] < 3:
+			return errors.New("temporary")
+		case webhook.Type == WEBHOOK_RECORDING:
+			if attempts[webhook.Type] == 2 {
+				done <- webhook.Type
+			}
+			return errors.New("permanent")
+		}
+		done <- webhook.Type
+		return nil
+	})
+	processor.MinBackoff = time.Millisecond
+	processor.MaxBackoff = 5 * time.Millisecond
+	processor.MaxAttempts = 3
+	processor.DeadLetters = deadLetters
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error)
+	go func() { stopped <- processor.Run(ctx) }()
+
+	for _, fixture := range []string{"./fixtures/webhook_room_update.json", "./fixtures/webhook_recording_update.json"} {
+		rec := httptest.NewRecorder()
+		processor.ServeHTTP(rec, signedWebhookRequest(t, "secret", fixture))
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("Expected 202 for %s, got %d", fixture, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	processor.ServeHTTP(rec, signedWebhookRequest(t, "wrong", "./fixtures/webhook_room_update.json"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for invalid signature, got %d", rec.Code)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			t.Fatal("Timeout waiting for webhooks to be processed")
+		}
+	}
+	for len(deadLetters.Items()) == 0 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-stopped
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts[WEBHOOK_ROOM] != 3 {
+		t.Errorf("Expected room update to succeed on third attempt, got %d attempts", attempts[WEBHOOK_ROOM])
+	}
+	items := deadLetters.Items()
+	if len(items) != 1 || items[0].Attempts != 3 || items[0].LastError != "permanent" {
+		t.Errorf("Expected recording update in dead letters, got %+v", items)
+	}
+}
+
+func TestWebhookProcessor_poisonDoesNotBlock(t *testing.T) {
+	queue := NewMemoryWebhookQueue()
+	healthy := make(chan struct{})
+	processor := NewWebhookProcessor("secret", queue, func(ctx context.Context, webhook *Webhook) error {
+		if webhook.Type == WEBHOOK_RECORDING {
+			return errors.New("poison")
+		}
+		close(healthy)
+		return nil
+	})
+	processor.Workers = 1
+	processor.MinBackoff = time.Hour
+	processor.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error)
+	go func() { stopped <- processor.Run(ctx) }()
+	for _, fixture := range []string{"./fixtures/webhook_recording_update.json", "./fixtures/webhook_room_update.json"} {
+		processor.ServeHTTP(httptest.NewRecorder(), signedWebhookRequest(t, "secret", fixture))
+	}
+	select {
+	case <-healthy:
+	case <-ctx.Done():
+		t.Fatal("Expected healthy webhook to be processed while the failed one waits")
+	}
+	cancel()
+	<-stopped
+	if queue.Len() != 1 {
+		t.Errorf("Expected failed webhook to wait in the queue, got %d", queue.Len())
+	}
+}
+
+type failingDeadLetters struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *failingDeadLetters) Put(ctx context.Context, item *QueuedWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls == 1 {
+		return errors.New("store down")
+	}
+	return nil
+}
+
+func TestWebhookProcessor_deadLetterFailure(t *testing.T) {
+	queue := NewMemoryWebhookQueue()
+	var mu sync.Mutex
+	attempts := 0
+	processor := NewWebhookProcessor("secret", queue, func(ctx context.Context, webhook *Webhook) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return errors.New("permanent")
+	})
+	deadLetters := &failingDeadLetters{}
+	processor.DeadLetters = deadLetters
+	processor.MaxAttempts = 1
+	processor.MaxBackoff = 5 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error)
+	go func() { stopped <- processor.Run(ctx) }()
+	processor.ServeHTTP(httptest.NewRecorder(), signedWebhookRequest(t, "secret", "./fixtures/webhook_room_update.json"))
+	for ctx.Err() == nil {
+		deadLetters.mu.Lock()
+		calls := deadLetters.calls
+		deadLetters.mu.Unlock()
+		if calls == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-stopped
+
+	mu.Lock()
+	defer mu.Unlock()
+	if deadLetters.calls != 2 || attempts != 1 || queue.Len() != 0 {
+		t.Errorf("Expected dead-lettering to be retried without processing, got %d puts, %d attempts",
+			deadLetters.calls, attempts)
+	}
+}
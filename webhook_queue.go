@@ -0,0 +1,261 @@
+package eyeson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QueuedWebhook is a verified webhook payload waiting to be processed.
+type QueuedWebhook struct {
+	ID         string    `json:"id"`
+	Payload    []byte    `json:"payload"`
+	ReceivedAt time.Time `json:"received_at"`
+	// Attempts is the number of failed processing attempts.
+	Attempts int `json:"attempts"`
+	// LastError is the error of the last failed attempt.
+	LastError string `json:"last_error,omitempty"`
+	// NotBefore delays the next attempt of a failed webhook.
+	NotBefore time.Time `json:"not_before"`
+}
+
+// WebhookQueue stores webhooks until they have been processed.
+type WebhookQueue interface {
+	// Enqueue stores a webhook. Failed webhooks are enqueued again with
+	// their attempts and NotBefore time, replacing the stored webhook of the
+	// same ID.
+	Enqueue(ctx context.Context, item *QueuedWebhook) error
+	// Dequeue waits for the next webhook whose NotBefore time has passed
+	// until the context is done. The webhook is handed out once, but kept in
+	// durable queues until it is acknowledged.
+	Dequeue(ctx context.Context) (*QueuedWebhook, error)
+	// Ack removes a processed webhook.
+	Ack(ctx context.Context, id string) error
+}
+
+// DeadLetterStore keeps webhooks which could not be processed.
+type DeadLetterStore interface {
+	Put(ctx context.Context, item *QueuedWebhook) error
+}
+
+// MemoryWebhookQueue is a WebhookQueue in memory. Queued webhooks are lost
+// when the process ends.
+type MemoryWebhookQueue struct {
+	mu     sync.Mutex
+	items  []*QueuedWebhook
+	signal chan struct{}
+}
+
+// NewMemoryWebhookQueue creates an empty queue.
+func NewMemoryWebhookQueue() *MemoryWebhookQueue {
+	return &MemoryWebhookQueue{signal: make(chan struct{}, 1)}
+}
+
+// Enqueue implements the WebhookQueue interface.
+func (q *MemoryWebhookQueue) Enqueue(ctx context.Context, item *QueuedWebhook) error {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Dequeue implements the WebhookQueue interface.
+func (q *MemoryWebhookQueue) Dequeue(ctx context.Context) (*QueuedWebhook, error) {
+	for {
+		item, wait := q.next()
+		if item != nil {
+			return item, nil
+		}
+		var timer *time.Timer
+		var ready <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			ready = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-q.signal:
+		case <-ready:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// next removes the first webhook due. Otherwise it provides the time until
+// the next delayed webhook is due, zero if there is none.
+func (q *MemoryWebhookQueue) next() (*QueuedWebhook, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	var wait time.Duration
+	for i, item := range q.items {
+		if delay := item.NotBefore.Sub(now); delay > 0 {
+			if wait == 0 || delay < wait {
+				wait = delay
+			}
+			continue
+		}
+		q.items = append(q.items[:i], q.items[i+1:]...)
+		if len(q.items) > 0 {
+			// wake up the next waiting worker
+			select {
+			case q.signal <- struct{}{}:
+			default:
+			}
+		}
+		return item, 0
+	}
+	return nil, wait
+}
+
+// Ack implements the WebhookQueue interface. Webhooks are removed from memory
+// queues on Dequeue already.
+func (q *MemoryWebhookQueue) Ack(ctx context.Context, id string) error {
+	return nil
+}
+
+// Len provides the number of waiting webhooks.
+func (q *MemoryWebhookQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// FileWebhookQueue is a WebhookQueue storing every webhook as JSON file in a
+// directory. Webhooks not acknowledged before the process ended are queued
+// again when the queue is opened.
+type FileWebhookQueue struct {
+	dir     string
+	pending *MemoryWebhookQueue
+}
+
+// OpenFileWebhookQueue opens the queue in the given directory, creating it if
+// needed, and queues all stored webhooks in order of arrival.
+func OpenFileWebhookQueue(dir string) (*FileWebhookQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	// remove files of writes interrupted by the end of the process
+	temps, err := filepath.Glob(filepath.Join(dir, "tmp-*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range temps {
+		if err = os.Remove(file); err != nil {
+			return nil, err
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	q := &FileWebhookQueue{dir: dir, pending: NewMemoryWebhookQueue()}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var item QueuedWebhook
+		if err = json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("Failed to read queued webhook %s: %s", file, err)
+		}
+		q.pending.Enqueue(context.Background(), &item)
+	}
+	return q, nil
+}
+
+func (q *FileWebhookQueue) path(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
+
+// Enqueue implements the WebhookQueue interface. The webhook is written to
+// disk before it is queued, including the attempts of failed webhooks.
+func (q *FileWebhookQueue) Enqueue(ctx context.Context, item *QueuedWebhook) error {
+	if item.ID == "" || strings.ContainsAny(item.ID, `/\.`) {
+		return fmt.Errorf("Invalid webhook id %q", item.ID)
+	}
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(q.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(raw); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), q.path(item.ID)); err != nil {
+		return err
+	}
+	return q.pending.Enqueue(ctx, item)
+}
+
+// Dequeue implements the WebhookQueue interface.
+func (q *FileWebhookQueue) Dequeue(ctx context.Context) (*QueuedWebhook, error) {
+	return q.pending.Dequeue(ctx)
+}
+
+// Ack implements the WebhookQueue interface. It deletes the file of the
+// webhook.
+func (q *FileWebhookQueue) Ack(ctx context.Context, id string) error {
+	err := os.Remove(q.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Len provides the number of waiting webhooks.
+func (q *FileWebhookQueue) Len() int {
+	return q.pending.Len()
+}
+
+// MemoryDeadLetterStore is a DeadLetterStore in memory.
+type MemoryDeadLetterStore struct {
+	mu    sync.Mutex
+	items []*QueuedWebhook
+}
+
+// NewMemoryDeadLetterStore creates an empty store.
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{}
+}
+
+// Put implements the DeadLetterStore interface.
+func (s *MemoryDeadLetterStore) Put(ctx context.Context, item *QueuedWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = append(s.items, item)
+	return nil
+}
+
+// Items provides all stored webhooks in order of arrival.
+func (s *MemoryDeadLetterStore) Items() []*QueuedWebhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*QueuedWebhook(nil), s.items...)
+}
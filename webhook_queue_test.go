@@ -0,0 +1,106 @@
+package eyeson
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryWebhookQueue(t *testing.T) {
+	q := NewMemoryWebhookQueue()
+	ctx := context.Background()
+	q.Enqueue(ctx, &QueuedWebhook{ID: "1"})
+	q.Enqueue(ctx, &QueuedWebhook{ID: "2"})
+	if q.Len() != 2 {
+		t.Errorf("Expected 2 queued webhooks, got %d", q.Len())
+	}
+	for _, id := range []string{"1", "2"} {
+		if item, err := q.Dequeue(ctx); err != nil || item.ID != id {
+			t.Errorf("Expected webhook %s, got %v (%v)", id, item, err)
+		}
+	}
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Dequeue(short); err != context.DeadlineExceeded {
+		t.Errorf("Expected Dequeue to wait for the context, got %v", err)
+	}
+
+	got := make(chan string)
+	go func() {
+		item, _ := q.Dequeue(ctx)
+		got <- item.ID
+	}()
+	time.Sleep(5 * time.Millisecond)
+	q.Enqueue(ctx, &QueuedWebhook{ID: "3"})
+	if id := <-got; id != "3" {
+		t.Errorf("Expected waiting Dequeue to receive webhook 3, got %s", id)
+	}
+}
+
+func TestMemoryWebhookQueue_delayed(t *testing.T) {
+	q := NewMemoryWebhookQueue()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	q.Enqueue(ctx, &QueuedWebhook{ID: "later", NotBefore: time.Now().Add(30 * time.Millisecond)})
+	q.Enqueue(ctx, &QueuedWebhook{ID: "now"})
+	start := time.Now()
+	for _, id := range []string{"now", "later"} {
+		if item, err := q.Dequeue(ctx); err != nil || item.ID != id {
+			t.Errorf("Expected webhook %s, got %v (%v)", id, item, err)
+		}
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Error("Expected delayed webhook to wait for its NotBefore time")
+	}
+}
+
+func TestFileWebhookQueue(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	q, err := OpenFileWebhookQueue(dir)
+	if err != nil {
+		t.Fatalf("Failed to open queue: %v", err)
+	}
+	for _, id := range []string{"001", "002", "003"} {
+		if err = q.Enqueue(ctx, &QueuedWebhook{ID: id, Payload: []byte(`{"type":"room_update"}`)}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	if err = q.Enqueue(ctx, &QueuedWebhook{ID: "../escape"}); err == nil {
+		t.Error("Expected invalid id to be rejected")
+	}
+	item, _ := q.Dequeue(ctx)
+	q.Ack(ctx, item.ID)
+	// the second webhook fails and is enqueued again, then the process ends
+	item, _ = q.Dequeue(ctx)
+	item.Attempts, item.LastError = 1, "temporary"
+	if err = q.Enqueue(ctx, item); err != nil {
+		t.Fatalf("Enqueue of failed webhook failed: %v", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "tmp-123"), []byte("{"), 0600); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+
+	reopened, err := OpenFileWebhookQueue(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen queue: %v", err)
+	}
+	if reopened.Len() != 2 {
+		t.Fatalf("Expected 2 webhooks after reopen, got %d", reopened.Len())
+	}
+	for _, id := range []string{"002", "003"} {
+		item, err := reopened.Dequeue(ctx)
+		if err != nil || item.ID != id || string(item.Payload) != `{"type":"room_update"}` {
+			t.Errorf("Expected webhook %s, got %+v (%v)", id, item, err)
+		}
+		if id == "002" && (item.Attempts != 1 || item.LastError != "temporary") {
+			t.Errorf("Expected failed attempt to be kept, got %+v", item)
+		}
+	}
+	if temps, _ := filepath.Glob(filepath.Join(dir, "tmp-*")); len(temps) != 0 {
+		t.Errorf("Expected temp files to be removed, got %v", temps)
+	}
+}
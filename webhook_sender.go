@@ -0,0 +1,66 @@
+package eyeson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+)
+
+// WebhookSender posts signed webhooks like the API does, to drive webhook
+// endpoints in tests and local development.
+type WebhookSender struct {
+	apiKey string
+	url    string
+	// Client sends the requests, http.DefaultClient by default.
+	Client *http.Client
+}
+
+// NewWebhookSender creates a sender signing with the given API key and
+// posting to the given URL.
+func NewWebhookSender(apiKey, url string) *WebhookSender {
+	return &WebhookSender{apiKey: apiKey, url: url}
+}
+
+// Send encodes the webhook, e.g. a *RoomWebhook, as JSON and posts it. It
+// provides the status code of the response.
+func (s *WebhookSender) Send(ctx context.Context, webhook interface{}) (int, error) {
+	payload, err := json.Marshal(webhook)
+	if err != nil {
+		return 0, err
+	}
+	return s.SendPayload(ctx, payload)
+}
+
+// SendFile posts the content of a file, e.g. a fixture, as payload.
+func (s *WebhookSender) SendFile(ctx context.Context, path string) (int, error) {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return s.SendPayload(ctx, payload)
+}
+
+// SendPayload signs and posts a raw payload.
+func (s *WebhookSender) SendPayload(ctx context.Context, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Eyeson-Signature", SignWebhook(s.apiKey, payload))
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
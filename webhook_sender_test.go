@@ -0,0 +1,55 @@
+package eyeson
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	payload := []byte(`{"type":"room_update"}`)
+	signature := SignWebhook("secret", payload)
+	if len(signature) != 64 {
+		t.Errorf("Expected hex encoded SHA-256 signature, got %q", signature)
+	}
+	if err := verifyWebhook("secret", payload, signature); err != nil {
+		t.Errorf("Expected signature to verify, got %v", err)
+	}
+	if err := verifyWebhook("other", payload, signature); err != ErrWebhookSignature {
+		t.Errorf("Expected signature of other key to fail, got %v", err)
+	}
+}
+
+func TestWebhookSender(t *testing.T) {
+	handler := NewWebhookHandler("secret")
+	var rooms []string
+	handler.OnRoomUpdate(func(ctx context.Context, webhook *Webhook) error {
+		rooms = append(rooms, webhook.Room.Id)
+		return nil
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	sender := NewWebhookSender("secret", server.URL)
+	ctx := context.Background()
+	if status, err := sender.SendFile(ctx, "./fixtures/webhook_room_update.json"); err != nil || status != http.StatusNoContent {
+		t.Errorf("Expected fixture to be accepted, got %d (%v)", status, err)
+	}
+	webhook := &RoomWebhook{
+		WebhookBase: WebhookBase{Type: WEBHOOK_ROOM, Timestamp: time.Now().Unix()},
+		Room:        EventRoom{ID: "typed", Ready: true},
+	}
+	if status, err := sender.Send(ctx, webhook); err != nil || status != http.StatusNoContent {
+		t.Errorf("Expected typed webhook to be accepted, got %d (%v)", status, err)
+	}
+	if len(rooms) != 2 || rooms[1] != "typed" {
+		t.Errorf("Expected both webhooks to be handled, got %v", rooms)
+	}
+
+	wrong := NewWebhookSender("wrong", server.URL)
+	if status, _ := wrong.SendFile(ctx, "./fixtures/webhook_snapshot_update.json"); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for wrong key, got %d", status)
+	}
+}
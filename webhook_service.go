@@ -1,6 +1,11 @@
 package eyeson
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 )
@@ -10,10 +15,16 @@ type WebhookService service
 
 // Register will assign an endpoint URL to the current ApiKey.
 func (srv *WebhookService) Register(endpoint, types string) error {
+	return srv.RegisterContext(context.Background(), endpoint, types)
+}
+
+// RegisterContext is like Register but uses the given context for the request.
+func (srv *WebhookService) RegisterContext(ctx context.Context, endpoint, types string) error {
+	ctx = withOperation(ctx, "webhooks.register", "")
 	data := url.Values{}
 	data.Set("url", endpoint)
 	data.Set("types", types)
-	req, err := srv.client.NewRequest(http.MethodPost, "/webhooks", data)
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodPost, "/webhooks", data)
 	if err != nil {
 		return err
 	}
@@ -22,35 +33,173 @@ func (srv *WebhookService) Register(endpoint, types string) error {
 	return err
 }
 
-// Get provides details about a registered webhook.
-func (srv *WebhookService) Get() (*WebhookDetails, error) {
-	req, err := srv.client.NewRequest(http.MethodGet, "/webhooks", nil)
+// RegisterTypes is like Register but takes the webhook types as set.
+func (srv *WebhookService) RegisterTypes(endpoint string, types ...WebhookType) error {
+	return srv.RegisterTypesContext(context.Background(), endpoint, types...)
+}
+
+// RegisterTypesContext is like RegisterTypes but uses the given context for
+// the request.
+func (srv *WebhookService) RegisterTypesContext(ctx context.Context, endpoint string, types ...WebhookType) error {
+	if len(types) == 0 {
+		return errors.New("Missing webhook types")
+	}
+	return srv.RegisterContext(ctx, endpoint, WebhookTypes(types).String())
+}
+
+// webhookList decodes a list of webhooks as well as a single webhook object.
+type webhookList []WebhookDetails
+
+func (l *webhookList) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, (*[]WebhookDetails)(l))
+	}
+	var details WebhookDetails
+	if err := json.Unmarshal(data, &details); err != nil {
+		return err
+	}
+	*l = nil
+	if details.Id != "" || details.Url != "" {
+		*l = webhookList{details}
+	}
+	return nil
+}
+
+// List provides details about all registered webhooks.
+func (srv *WebhookService) List() ([]WebhookDetails, error) {
+	return srv.ListContext(context.Background())
+}
+
+// ListContext is like List but uses the given context for the request.
+func (srv *WebhookService) ListContext(ctx context.Context) ([]WebhookDetails, error) {
+	ctx = withOperation(ctx, "webhooks.list", "")
+	return srv.list(ctx)
+}
+
+func (srv *WebhookService) list(ctx context.Context) ([]WebhookDetails, error) {
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodGet, "/webhooks", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var details WebhookDetails
-	_, err = srv.client.Do(req, &details)
+	var webhooks webhookList
+	_, err = srv.client.Do(req, &webhooks)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Get provides details about a registered webhook. If several webhooks are
+// registered, it provides the first one, see List.
+func (srv *WebhookService) Get() (*WebhookDetails, error) {
+	return srv.GetContext(context.Background())
+}
+
+// GetContext is like Get but uses the given context for the request.
+func (srv *WebhookService) GetContext(ctx context.Context) (*WebhookDetails, error) {
+	ctx = withOperation(ctx, "webhooks.get", "")
+	webhooks, err := srv.list(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &details, err
+	if len(webhooks) == 0 {
+		return &WebhookDetails{}, nil
+	}
+	return &webhooks[0], nil
 }
 
 // Unregister will clear the current webhook.
 func (srv *WebhookService) Unregister() error {
-	w, err := srv.Get()
+	return srv.UnregisterContext(context.Background())
+}
+
+// UnregisterContext is like Unregister but uses the given context for the
+// requests.
+func (srv *WebhookService) UnregisterContext(ctx context.Context) error {
+	ctx = withOperation(ctx, "webhooks.unregister", "")
+	w, err := srv.GetContext(ctx)
 	if err != nil {
 		return err
 	}
-	req, err := srv.client.NewRequest(http.MethodDelete, "/webhooks/"+w.Id, nil)
+	return srv.delete(ctx, w.Id)
+}
+
+// UnregisterID will clear the webhook with the given id.
+func (srv *WebhookService) UnregisterID(id string) error {
+	return srv.UnregisterIDContext(context.Background(), id)
+}
+
+// UnregisterIDContext is like UnregisterID but uses the given context for the
+// request.
+func (srv *WebhookService) UnregisterIDContext(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("Missing webhook id")
+	}
+	ctx = withOperation(ctx, "webhooks.unregister", "")
+	return srv.delete(ctx, id)
+}
+
+func (srv *WebhookService) delete(ctx context.Context, id string) error {
+	req, err := srv.client.NewRequestWithContext(ctx, http.MethodDelete, "/webhooks/"+url.PathEscape(id), nil)
 	if err != nil {
 		return err
 	}
 
 	_, err = srv.client.Do(req, nil)
+	return err
+}
+
+// Ensure makes the endpoint the only webhook registered, with exactly the
+// given types. It is idempotent: a matching webhook is kept as is. Otherwise
+// the endpoint is registered first and other webhooks are cleared afterwards,
+// so a failed registration keeps the existing webhooks. It provides the
+// details of the registered webhook.
+func (srv *WebhookService) Ensure(endpoint string, types ...WebhookType) (*WebhookDetails, error) {
+	return srv.EnsureContext(context.Background(), endpoint, types...)
+}
+
+// EnsureContext is like Ensure but uses the given context for the requests.
+func (srv *WebhookService) EnsureContext(ctx context.Context, endpoint string, types ...WebhookType) (*WebhookDetails, error) {
+	if len(types) == 0 {
+		return nil, errors.New("Missing webhook types")
+	}
+	webhooks, err := srv.ListContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	found := findWebhook(webhooks, endpoint, types)
+	if found == nil {
+		if err = srv.RegisterTypesContext(ctx, endpoint, types...); err != nil {
+			return nil, err
+		}
+		if webhooks, err = srv.ListContext(ctx); err != nil {
+			return nil, err
+		}
+		if found = findWebhook(webhooks, endpoint, types); found == nil {
+			return nil, fmt.Errorf("Registered webhook %s is not listed", endpoint)
+		}
+	}
+
+	for _, w := range webhooks {
+		if w.Id == found.Id {
+			continue
+		}
+		err = srv.UnregisterIDContext(ctx, w.Id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// findWebhook provides the webhook with the given endpoint and types.
+func findWebhook(webhooks []WebhookDetails, endpoint string, types WebhookTypes) *WebhookDetails {
+	for i := range webhooks {
+		if webhooks[i].Url == endpoint && webhooks[i].TypeSet().Equal(types) {
+			found := webhooks[i]
+			return &found
+		}
 	}
 	return nil
 }
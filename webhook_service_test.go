@@ -39,3 +39,99 @@ func TestWebhookUnregister(t *testing.T) {
 		t.Errorf("Webhook unregister not successfull: %v", err)
 	}
 }
+
+func TestWebhookList(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	response := `[{"id":"1","url":"https://example.com/a","types":"room_update"},
+		{"id":"2","url":"https://example.com/b","types":["snapshot_update"]}]`
+	mux.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		w.WriteHeader(200)
+		w.Write([]byte(response))
+	})
+
+	webhooks, err := client.Webhook.List()
+	if err != nil {
+		t.Fatalf("Webhook list not successfull: %v", err)
+	}
+	if len(webhooks) != 2 || webhooks[1].Id != "2" ||
+		!webhooks[1].TypeSet().Contains(WebhookSnapshotUpdate) {
+		t.Errorf("Unexpected webhooks %v", webhooks)
+	}
+
+	response = `{"id":"42","url":"https://example.com/a","types":"room_update"}`
+	if webhooks, err = client.Webhook.List(); err != nil || len(webhooks) != 1 {
+		t.Errorf("Expected single webhook, got %v, %v", webhooks, err)
+	}
+	response = `{}`
+	if webhooks, err = client.Webhook.List(); err != nil || len(webhooks) != 0 {
+		t.Errorf("Expected no webhooks, got %v, %v", webhooks, err)
+	}
+}
+
+func TestWebhookRegisterTypes(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testFormValues(t, r, values{"url": "https://example.com/webhook-listener",
+			"types": "room_update,recording_update"})
+		w.WriteHeader(201)
+	})
+
+	err := client.Webhook.RegisterTypes("https://example.com/webhook-listener",
+		WebhookRoomUpdate, WebhookRecordingUpdate)
+	if err != nil {
+		t.Errorf("Webhook register not successfull: %v", err)
+	}
+	if err = client.Webhook.RegisterTypes("https://example.com/webhook-listener"); err == nil {
+		t.Error("Expected error without types")
+	}
+}
+
+func TestWebhookUnregisterID(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/webhooks/7", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(204)
+	})
+
+	if err := client.Webhook.UnregisterID("7"); err != nil {
+		t.Errorf("Webhook unregister not successfull: %v", err)
+	}
+	if err := client.Webhook.UnregisterID(""); err == nil {
+		t.Error("Expected error without id")
+	}
+}
+
+func TestWebhookEnsure_registerFirst(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	registerStatus := 422
+	mux.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(registerStatus)
+			return
+		}
+		w.WriteHeader(200)
+		w.Write([]byte(`[{"id":"1","url":"https://example.com/old","types":"room_update"}]`))
+	})
+	mux.HandleFunc("/webhooks/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected existing webhook to be kept")
+		w.WriteHeader(204)
+	})
+
+	if _, err := client.Webhook.Ensure("https://example.com/new", WebhookRoomUpdate); err == nil {
+		t.Error("Expected failed registration to be returned")
+	}
+	registerStatus = 201
+	if _, err := client.Webhook.Ensure("https://example.com/new", WebhookRoomUpdate); err == nil {
+		t.Error("Expected error for registration missing in the list")
+	}
+}
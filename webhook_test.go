@@ -2,8 +2,10 @@ package eyeson
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestWebhookUnmarshalRoom(t *testing.T) {
@@ -24,6 +26,9 @@ func TestWebhookUnmarshalRoom(t *testing.T) {
 	if webhook.Room.StartedAt.Weekday().String() != "Wednesday" {
 		t.Errorf("Expected meeting from Wednesday, got %v", webhook.Room.StartedAt.Weekday())
 	}
+	if webhook.Timestamp != 1637738200 || webhook.Time().Year() != 2021 {
+		t.Errorf("Expected timestamp 1637738200, got %v", webhook.Timestamp)
+	}
 	if webhook.Room.Shutdown != false {
 		t.Errorf("Expected meeting to be active, got %v", webhook.Room.Shutdown)
 	}
@@ -71,3 +76,71 @@ func TestWebhookUnmarshalSnapshot(t *testing.T) {
 		t.Errorf("Expected room identifier demo, got %v", webhook.Snapshot.Room.Id)
 	}
 }
+
+func TestWebhook_freshness(t *testing.T) {
+	webhook := Webhook{Timestamp: time.Now().Add(-30 * time.Second).Unix()}
+	if err := webhook.CheckFreshness(time.Minute); err != nil {
+		t.Errorf("Expected webhook to be fresh, got %v", err)
+	}
+	if err := webhook.CheckFreshness(10 * time.Second); !errors.Is(err, ErrWebhookStale) {
+		t.Errorf("Expected ErrWebhookStale, got %v", err)
+	}
+	webhook.Timestamp = time.Now().Add(time.Hour).Unix()
+	if err := webhook.CheckFreshness(time.Minute); !errors.Is(err, ErrWebhookStale) {
+		t.Errorf("Expected webhook from the future to be stale, got %v", err)
+	}
+}
+
+func TestWebhookDetailsUnmarshal(t *testing.T) {
+	sample, err := os.ReadFile("./fixtures/webhook_details.json")
+	if err != nil {
+		t.Errorf("Failed to read fixture file: %v", err)
+	}
+	var details WebhookDetails
+	if err = json.Unmarshal(sample, &details); err != nil {
+		t.Fatalf("Failed to decode sample: %v", err)
+	}
+	if len(details.Types) != 1 || !details.TypeSet().Equal(WebhookTypes{WebhookRoomUpdate}) {
+		t.Errorf("Expected types room_update, got %v", details.Types)
+	}
+	if details.LastRequestSentAt.Year() != 2020 {
+		t.Errorf("Expected last request from 2020, got %v", details.LastRequestSentAt)
+	}
+	if details.ResponseCode() != 204 || !details.Healthy() {
+		t.Errorf("Expected healthy response code 204, got %v", details.LastResponseCode)
+	}
+
+	sample = []byte(`{"id":"1","types":["room_update","snapshot_update"],
+		"last_request_sent_at":null,"last_response_code":500}`)
+	if err = json.Unmarshal(sample, &details); err != nil {
+		t.Fatalf("Failed to decode details: %v", err)
+	}
+	if details.TypeSet().String() != "room_update,snapshot_update" {
+		t.Errorf("Expected two types, got %v", details.Types)
+	}
+	if !details.LastRequestSentAt.IsZero() {
+		t.Errorf("Expected no last request, got %v", details.LastRequestSentAt)
+	}
+	if details.ResponseCode() != 500 || details.Healthy() {
+		t.Errorf("Expected unhealthy response code 500, got %v", details.LastResponseCode)
+	}
+	if details = (WebhookDetails{}); !details.Healthy() {
+		t.Error("Expected webhook without delivery to be healthy")
+	}
+}
+
+func TestWebhookTypes(t *testing.T) {
+	types := ParseWebhookTypes(" room_update,recording_update,,room_update")
+	if types.String() != "room_update,recording_update" {
+		t.Errorf("Expected two types, got %v", types)
+	}
+	if !types.Contains(WebhookRecordingUpdate) || types.Contains(WebhookSnapshotUpdate) {
+		t.Errorf("Unexpected types %v", types)
+	}
+	if !types.Equal(WebhookTypes{WebhookRecordingUpdate, WebhookRoomUpdate}) {
+		t.Errorf("Expected types to equal in any order")
+	}
+	if types.Equal(WebhookTypes{WebhookRoomUpdate}) {
+		t.Errorf("Expected types to differ")
+	}
+}